@@ -0,0 +1,185 @@
+package gohetz
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"./models"
+)
+
+const actionsUrl = "/actions"
+
+// ActionListOpts specifies options for listing actions.
+type ActionListOpts struct {
+	ListOpts
+	Status []models.Status // Only return actions with one of these statuses
+	Sort   []string        // Sort order, e.g. "started:desc"
+}
+
+func valuesForActionListOpts(opts ActionListOpts) url.Values {
+	vals := valuesForListOpts(opts.ListOpts)
+	for _, status := range opts.Status {
+		vals.Add("status", string(status))
+	}
+	for _, sort := range opts.Sort {
+		vals.Add("sort", sort)
+	}
+	return vals
+}
+
+// GetServerActions returns a single page of actions of the server with the given ID.
+func (c *Client) GetServerActions(ctx context.Context, serverID int, opts ActionListOpts) ([]*models.Action, *Response, error) {
+	path := fmt.Sprintf("%s%d/actions?%s", serversUrl, serverID, valuesForActionListOpts(opts).Encode())
+	p, resp, err := c.listActions(ctx, path)
+	if err != nil {
+		return nil, resp, err
+	}
+	actions := make([]*models.Action, len(p.Actions))
+	for i := range p.Actions {
+		actions[i] = &models.Action{Server: p.Actions[i]}
+	}
+	return actions, resp, nil
+}
+
+// GetAllActions returns all actions matching opts, following pagination.
+// The filters in opts are kept for every page; opts.Page is ignored.
+func (c *Client) GetAllActions(ctx context.Context, opts ActionListOpts) (*models.Actions, error) {
+	opts.ListOpts = allListOpts(opts.ListOpts)
+	actions := &models.Actions{}
+	_, err := c.all(func(page int) (*Response, error) {
+		opts.Page = page
+		p, resp, err := c.listActions(ctx, actionsUrl+"?"+valuesForActionListOpts(opts).Encode())
+		if err != nil {
+			return resp, err
+		}
+		actions.Actions = append(actions.Actions, p.Actions...)
+		return resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return actions, nil
+}
+
+// LatestAction returns the most recently started action of the server with
+// the given ID, or nil if the server has no actions.
+func (c *Client) LatestAction(ctx context.Context, serverID int) (*models.Action, error) {
+	actions, _, err := c.GetServerActions(ctx, serverID, ActionListOpts{
+		ListOpts: ListOpts{PerPage: 1},
+		Sort:     []string{"started:desc"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(actions) == 0 {
+		return nil, nil
+	}
+	return actions[0], nil
+}
+
+func (c *Client) listActions(ctx context.Context, path string) (*models.Actions, *Response, error) {
+	req, err := c.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw, err := c.Do(req, nil)
+	if err != nil {
+		return nil, raw, err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(raw.Body)
+	if err != nil {
+		return nil, raw, err
+	}
+
+	var actions models.Actions
+	if err := c.decode(bodyBytes, &actions); err != nil {
+		return nil, raw, err
+	}
+
+	return &actions, raw, nil
+}
+
+// WaitForAction polls the given action every poll interval until it has
+// finished. It returns nil if the action succeeded and an Error built from
+// the action's error if it failed. It returns ctx.Err() if ctx is done first.
+func (c *Client) WaitForAction(ctx context.Context, action *models.Action) error {
+	return c.WaitForActionWithProgress(ctx, action, nil)
+}
+
+// WaitForActionWithProgress is like WaitForAction, but calls progress with
+// the progress of the action in percent each time it changes, starting with
+// the progress of action itself. This can be used to drive progress bars. A
+// nil progress is ignored.
+func (c *Client) WaitForActionWithProgress(ctx context.Context, action *models.Action, progress func(percent int)) error {
+	a := action.Server
+	lastProgress := -1
+	for {
+		if percent := a.ProgressPercent(); progress != nil && percent != lastProgress {
+			progress(percent)
+			lastProgress = percent
+		}
+		switch a.Status {
+		case models.StatusSuccess:
+			return nil
+		case models.StatusError:
+			return errorFromAction(a)
+		}
+
+		if err := sleepContext(ctx, c.pollInterval); err != nil {
+			return err
+		}
+		current, err := c.getAction(ctx, int(a.ID))
+		if err != nil {
+			return err
+		}
+		a = current.Server
+	}
+}
+
+func (c *Client) getAction(ctx context.Context, id int) (*models.Action, error) {
+	req, err := c.NewRequest(ctx, http.MethodGet, fmt.Sprintf("%s/%d", actionsUrl, id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := c.Do(req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(raw.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var action models.Action
+	if err := c.decode(bodyBytes, &action); err != nil {
+		return nil, err
+	}
+
+	return &action, nil
+}
+
+func errorFromAction(a models.ActionClass) error {
+	if a.Error == nil {
+		return Error{
+			Code:    ErrorCodeUnknownError,
+			Message: fmt.Sprintf("action %d (%s) failed", a.ID, a.Command),
+		}
+	}
+	return Error{
+		Code:    ErrorCode(a.Error.Code),
+		Message: a.Error.Message,
+	}
+}
+
+// CancelAction always returns ErrNotSupported without calling the API, as
+// running actions cannot be cancelled.
+func (c *Client) CancelAction(ctx context.Context, id int) error {
+	return notSupported("CancelAction", "the API has no endpoint for cancelling actions; wait for the action to finish instead")
+}
@@ -0,0 +1,127 @@
+package gohetz
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"./models"
+)
+
+func TestWaitForActionWithProgress(t *testing.T) {
+	responses := []string{
+		`{"action":{"id":1,"status":"running","progress":50}}`,
+		`{"action":{"id":1,"status":"running","progress":50}}`,
+		`{"action":{"id":1,"status":"success","progress":100}}`,
+	}
+	var polls int32
+	var progress []int
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&polls, 1))
+		if n > len(responses) {
+			n = len(responses)
+		}
+		writeJSON(w, http.StatusOK, responses[n-1])
+	}, WithPollInterval(0))
+
+	action := &models.Action{Server: models.ActionClass{ID: 1, Status: models.StatusRunning}}
+	err := client.WaitForActionWithProgress(context.Background(), action, func(percent int) {
+		progress = append(progress, percent)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{0, 50, 100}; !reflect.DeepEqual(progress, want) {
+		t.Errorf("expected progress %v, got %v", want, progress)
+	}
+	if polls != 3 {
+		t.Errorf("expected 3 polls, got %d", polls)
+	}
+}
+
+func TestGetServerActions(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/servers/1/actions" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if got := q["status"]; !reflect.DeepEqual(got, []string{"running", "error"}) {
+			t.Errorf("expected status filters running and error, got %v", got)
+		}
+		if q.Get("page") != "2" || q.Get("per_page") != "1" {
+			t.Errorf("expected page 2 of size 1, got %v", q)
+		}
+		writeJSON(w, http.StatusOK, `{"actions":[{"id":7,"command":"start_server","status":"running"}],"meta":{"pagination":{"page":2,"per_page":1,"previous_page":1,"next_page":3,"last_page":3,"total_entries":3}}}`)
+	})
+
+	actions, resp, err := client.GetServerActions(context.Background(), 1, ActionListOpts{
+		ListOpts: ListOpts{Page: 2, PerPage: 1},
+		Status:   []models.Status{models.StatusRunning, models.StatusError},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(actions) != 1 || actions[0].Server.ID != 7 || actions[0].Server.Status != models.StatusRunning {
+		t.Errorf("expected running action 7, got %v", actions)
+	}
+	if p := resp.Meta.Pagination; p == nil || p.Page != 2 || p.NextPage != 3 || p.TotalEntries != 3 {
+		t.Errorf("expected pagination of page 2 of 3, got %+v", p)
+	}
+}
+
+func TestLatestAction(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if q := r.URL.Query(); q.Get("sort") != "started:desc" || q.Get("per_page") != "1" {
+			t.Errorf("expected the newest action only, got %v", q)
+		}
+		writeJSON(w, http.StatusOK, `{"actions":[{"id":9,"command":"stop_server","status":"success"}]}`)
+	})
+
+	action, err := client.LatestAction(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if action == nil || action.Server.ID != 9 {
+		t.Errorf("expected action 9, got %v", action)
+	}
+}
+
+func TestGetAllActions(t *testing.T) {
+	var pages []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/actions" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if got := q["status"]; !reflect.DeepEqual(got, []string{"error"}) {
+			t.Errorf("expected the status filter on every page, got %v", got)
+		}
+		pages = append(pages, q.Get("page"))
+		switch q.Get("page") {
+		case "1":
+			writeJSON(w, http.StatusOK, `{"actions":[{"id":1,"status":"error"},{"id":2,"status":"error"}],"meta":{"pagination":{"page":1,"per_page":2,"next_page":2,"last_page":2}}}`)
+		default:
+			writeJSON(w, http.StatusOK, `{"actions":[{"id":3,"status":"error"}],"meta":{"pagination":{"page":2,"per_page":2,"previous_page":1,"last_page":2}}}`)
+		}
+	})
+
+	actions, err := client.GetAllActions(context.Background(), ActionListOpts{
+		ListOpts: ListOpts{Page: 5, PerPage: 2},
+		Status:   []models.Status{models.StatusError},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int64
+	for _, a := range actions.Actions {
+		ids = append(ids, a.ID)
+	}
+	if !reflect.DeepEqual(ids, []int64{1, 2, 3}) {
+		t.Errorf("expected actions 1, 2 and 3, got %v", ids)
+	}
+	if !reflect.DeepEqual(pages, []string{"1", "2"}) {
+		t.Errorf("expected pages 1 and 2, got %v", pages)
+	}
+}
@@ -4,14 +4,19 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"math"
+	"math/rand"
+	"mime"
 	"net/http"
+	"net/http/httputil"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"./models"
@@ -45,16 +50,51 @@ func ExponentialBackoff(b float64, d time.Duration) BackoffFunc {
 	}
 }
 
+var (
+	jitterMu   sync.Mutex
+	jitterRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// ExponentialBackoffWithJitter returns a BackoffFunc which implements an
+// exponential backoff like ExponentialBackoff and adds a random jitter in
+// [0, maxJitter), so that concurrent clients do not retry in lockstep.
+func ExponentialBackoffWithJitter(b float64, d time.Duration, maxJitter time.Duration) BackoffFunc {
+	backoff := ExponentialBackoff(b, d)
+	return func(retries int) time.Duration {
+		wait := backoff(retries)
+		if maxJitter <= 0 {
+			return wait
+		}
+		jitterMu.Lock()
+		jitter := time.Duration(jitterRand.Int63n(int64(maxJitter)))
+		jitterMu.Unlock()
+		return wait + jitter
+	}
+}
+
 // Client is a client for the Hetzner Cloud API.
 type Client struct {
 	endpoint           string
+	pathPrefix         string
 	token              string
 	pollInterval       time.Duration
 	backoffFunc        BackoffFunc
 	httpClient         *http.Client
+	transportOptions   []func(*http.Transport)
 	applicationName    string
 	applicationVersion string
 	userAgent          string
+	optionErr          error
+	retryCallback      func(attempt int, err error, wait time.Duration)
+	strictDecode       bool
+	useNumber          bool
+	strictContentType  bool
+	debugWriter        io.Writer
+	maxRetries         int
+	retryOn5xx         bool
+
+	rateLimitMu   sync.Mutex
+	lastRateLimit Ratelimit
 }
 
 // A ClientOption is used to configure a Client.
@@ -67,6 +107,18 @@ func WithEndpoint(endpoint string) ClientOption {
 	}
 }
 
+// WithPathPrefix configures a Client to prepend prefix to the path of each
+// request, for APIs mounted under a subpath, e.g. behind a gateway. Leading
+// and trailing slashes of prefix are ignored.
+func WithPathPrefix(prefix string) ClientOption {
+	return func(client *Client) {
+		client.pathPrefix = ""
+		if prefix = strings.Trim(prefix, "/"); prefix != "" {
+			client.pathPrefix = "/" + prefix
+		}
+	}
+}
+
 // WithToken configures a Client to use the specified token for authentication.
 func WithToken(token string) ClientOption {
 	return func(client *Client) {
@@ -82,13 +134,84 @@ func WithPollInterval(pollInterval time.Duration) ClientOption {
 	}
 }
 
-// WithBackoffFunc configures a Client to use the specified backoff function.
+// WithBackoffFunc configures a Client to use the specified backoff function
+// instead of the default jittered exponential backoff.
 func WithBackoffFunc(f BackoffFunc) ClientOption {
 	return func(client *Client) {
 		client.backoffFunc = f
 	}
 }
 
+// WithRetryCallback configures a Client to call f before each retry of a
+// request. attempt starts at 1, err is the error that caused the retry and
+// wait is the backoff duration before the next attempt.
+func WithRetryCallback(f func(attempt int, err error, wait time.Duration)) ClientOption {
+	return func(client *Client) {
+		client.retryCallback = f
+	}
+}
+
+// WithMaxRetries configures a Client to retry a request at most n times
+// before giving up. A negative n is treated as 0.
+func WithMaxRetries(n int) ClientOption {
+	return func(client *Client) {
+		if n < 0 {
+			n = 0
+		}
+		client.maxRetries = n
+	}
+}
+
+// WithRetryOn5xx configures whether a Client retries requests failing with a
+// 5xx status code, using the same backoff as for rate limits. It is enabled
+// by default. Only idempotent requests (GET, HEAD, PUT, DELETE) are retried,
+// as a POST failing at a proxy may still have been carried out by the API.
+// 501 Not Implemented is never retried, nor are 4xx errors other than rate
+// limits.
+func WithRetryOn5xx(retry bool) ClientOption {
+	return func(client *Client) {
+		client.retryOn5xx = retry
+	}
+}
+
+// WithStrictDecode configures a Client to reject responses containing fields
+// unknown to the model they are decoded into, for all client methods as well
+// as values passed to Do. This helps to detect schema drift in tests and
+// should usually be left off in production.
+func WithStrictDecode() ClientOption {
+	return func(client *Client) {
+		client.strictDecode = true
+	}
+}
+
+// WithUseNumber configures a Client to decode JSON numbers into interface{}
+// values as json.Number instead of float64, e.g. for maps passed to Do. This
+// keeps large IDs exact where no typed model is involved; the models' ID
+// fields are int64 and exact already.
+func WithUseNumber() ClientOption {
+	return func(client *Client) {
+		client.useNumber = true
+	}
+}
+
+// WithStrictContentType configures a Client to only parse error and meta
+// information from responses with an application/json content type. By
+// default, media types with a +json suffix and text/json are accepted as
+// well, as some proxies rewrite the header.
+func WithStrictContentType() ClientOption {
+	return func(client *Client) {
+		client.strictContentType = true
+	}
+}
+
+// WithDebugWriter configures a Client to write each request and response to
+// w, for debugging. The token is masked in the dumped Authorization header.
+func WithDebugWriter(w io.Writer) ClientOption {
+	return func(client *Client) {
+		client.debugWriter = w
+	}
+}
+
 // WithApplication configures a Client with the given application name and
 // application version. The version may be blank. Programs are encouraged
 // to at least set an application name.
@@ -99,19 +222,78 @@ func WithApplication(name, version string) ClientOption {
 	}
 }
 
-// NewClient creates a new client.
+// WithProxy configures a Client to send its requests through the proxy at
+// proxyURL. An invalid URL, or an HTTP client whose transport is not an
+// *http.Transport, is reported by the first request made with the Client.
+func WithProxy(proxyURL string) ClientOption {
+	return func(client *Client) {
+		u, err := url.Parse(proxyURL)
+		if err == nil && (u.Scheme == "" || u.Host == "") {
+			err = fmt.Errorf("missing scheme or host in %q", proxyURL)
+		}
+		if err != nil {
+			client.optionErr = fmt.Errorf("hcloud: invalid proxy URL: %s", err)
+			return
+		}
+		client.transportOptions = append(client.transportOptions, func(t *http.Transport) {
+			t.Proxy = http.ProxyURL(u)
+		})
+	}
+}
+
+// WithConnectionPool configures the connection pool of a Client's transport,
+// which helps batch tools issuing many requests. An HTTP client whose
+// transport is not an *http.Transport is reported by the first request made
+// with the Client.
+func WithConnectionPool(maxIdle, maxIdlePerHost int, idleTimeout time.Duration) ClientOption {
+	return func(client *Client) {
+		client.transportOptions = append(client.transportOptions, func(t *http.Transport) {
+			t.MaxIdleConns = maxIdle
+			t.MaxIdleConnsPerHost = maxIdlePerHost
+			t.IdleConnTimeout = idleTimeout
+		})
+	}
+}
+
+// WithHTTPClient configures a Client to perform HTTP requests with httpClient.
+// A nil httpClient is ignored. Options tuning the transport, like WithProxy,
+// are applied to a copy of httpClient regardless of their order.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(client *Client) {
+		if httpClient != nil {
+			client.httpClient = httpClient
+		}
+	}
+}
+
+// defaultBackoff is the backoff of clients without WithBackoffFunc.
+func defaultBackoff() BackoffFunc {
+	return ExponentialBackoffWithJitter(2, 500*time.Millisecond, 500*time.Millisecond)
+}
+
+// NewClient creates a new client. Unless configured otherwise, it backs off
+// exponentially starting at 500ms with up to 500ms of random jitter, so
+// clients hitting the same limit do not retry in lockstep. Clients created
+// before the jitter was added waited exactly 500ms * 2^retries; pass
+// WithBackoffFunc(ExponentialBackoff(2, 500*time.Millisecond)) to keep that.
 func NewClient(options ...ClientOption) *Client {
 	client := &Client{
 		endpoint:     Endpoint,
-		httpClient:   &http.Client{},
-		backoffFunc:  ExponentialBackoff(2, 500*time.Millisecond),
+		backoffFunc:  defaultBackoff(),
 		pollInterval: 500 * time.Millisecond,
+		maxRetries:   5,
+		retryOn5xx:   true,
 	}
 
 	for _, option := range options {
 		option(client)
 	}
 
+	if client.httpClient == nil {
+		client.httpClient = &http.Client{}
+	}
+	client.applyTransportOptions()
+
 	client.buildUserAgent()
 
 	return client
@@ -120,10 +302,13 @@ func NewClient(options ...ClientOption) *Client {
 // NewRequest creates an HTTP request against the API. The returned request
 // is assigned with ctx and has all necessary headers set (auth, user agent, etc.).
 func (c *Client) NewRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
-	url := c.endpoint + path
+	if c.optionErr != nil {
+		return nil, c.optionErr
+	}
+	url := c.endpoint + c.pathPrefix + path
 	req, err := http.NewRequest(method, url, body)
 	if err != nil {
-		return nil, err
+		return nil, c.redactError(err)
 	}
 	req.Header.Set("User-Agent", c.userAgent)
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
@@ -134,13 +319,41 @@ func (c *Client) NewRequest(ctx context.Context, method, path string, body io.Re
 	return req, nil
 }
 
+// NewRequestWithQuery is like NewRequest, but adds query to the query
+// parameters of path. This allows using API filters the library does not
+// model yet.
+func (c *Client) NewRequestWithQuery(ctx context.Context, method, path string, query url.Values, body io.Reader) (*http.Request, error) {
+	if len(query) > 0 {
+		sep := "?"
+		if strings.Contains(path, "?") {
+			sep = "&"
+		}
+		path += sep + query.Encode()
+	}
+	return c.NewRequest(ctx, method, path, body)
+}
+
+// NewJSONRequest is like NewRequest, but marshals body to JSON to use as the
+// request body. A nil body results in a request without a body.
+func (c *Client) NewJSONRequest(ctx context.Context, method, path string, body interface{}) (*http.Request, error) {
+	if body == nil {
+		return c.NewRequest(ctx, method, path, nil)
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	return c.NewRequest(ctx, method, path, bytes.NewReader(b))
+}
+
 // Do performs an HTTP request against the API.
 func (c *Client) Do(r *http.Request, v interface{}) (*Response, error) {
 	var retries int
 	for {
+		c.dumpRequest(r)
 		resp, err := c.httpClient.Do(r)
 		if err != nil {
-			return nil, err
+			return nil, c.redactError(err)
 		}
 		response := &Response{Response: resp}
 
@@ -151,29 +364,62 @@ func (c *Client) Do(r *http.Request, v interface{}) (*Response, error) {
 		}
 		resp.Body.Close()
 		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		c.dumpResponse(resp)
 
-		if err = response.readMeta(body); err != nil {
+		if err = response.readMeta(body, c.strictContentType); err != nil {
 			return response, fmt.Errorf("hcloud: error reading response meta data: %s", err)
 		}
+		if !response.Meta.Ratelimit.Reset.IsZero() {
+			c.setLastRateLimit(response.Meta.Ratelimit)
+		}
 
 		if resp.StatusCode >= 400 && resp.StatusCode <= 599 {
-			err = errorFromResponse(resp, body)
-			if err == nil {
+			err = errorFromResponse(resp, body, c.strictContentType)
+			switch {
+			case err == nil && resp.StatusCode == http.StatusConflict:
+				err = Error{
+					Code:    ErrorCodeConflict,
+					Message: "resource is in use or was changed concurrently",
+				}
+			case err == nil:
 				err = fmt.Errorf("hcloud: server responded with status code %d", resp.StatusCode)
-			} else {
-				if err, ok := err.(Error); ok && err.Code == ErrorCodeRateLimitExceeded {
-					c.backoff(retries)
-					retries++
-					continue
+			}
+
+			var (
+				retry   bool
+				hint    time.Duration
+				hasHint bool
+			)
+			switch {
+			case IsError(err, ErrorCodeRateLimitExceeded):
+				retry = true
+				hint, hasHint = response.rateLimitDelay()
+			case c.retryOn5xx && retryableServerError(r.Method, resp.StatusCode):
+				retry = true
+				hint, hasHint = response.retryAfter()
+			}
+			if !retry {
+				return response, err
+			}
+			if retries >= c.maxRetries {
+				return response, RetriesExceededError{Attempts: retries + 1, Err: err}
+			}
+			if err := c.backoff(r.Context(), retries, err, hint, hasHint); err != nil {
+				return response, err
+			}
+			if r.GetBody != nil {
+				if r.Body, err = r.GetBody(); err != nil {
+					return response, err
 				}
 			}
-			return response, err
+			retries++
+			continue
 		}
 		if v != nil {
 			if w, ok := v.(io.Writer); ok {
 				_, err = io.Copy(w, bytes.NewReader(body))
 			} else {
-				err = json.Unmarshal(body, v)
+				err = c.decode(body, v)
 			}
 		}
 
@@ -181,8 +427,264 @@ func (c *Client) Do(r *http.Request, v interface{}) (*Response, error) {
 	}
 }
 
-func (c *Client) backoff(retries int) {
-	time.Sleep(c.backoffFunc(retries))
+// retryableServerError reports whether a request with the given method that
+// failed with status may be sent again.
+func retryableServerError(method string, status int) bool {
+	if status < 500 || status == http.StatusNotImplemented {
+		return false
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// DoWithRateLimitWait runs f and, as long as it fails with a rate limit error,
+// waits until the rate limit resets and runs it again, at most as many times
+// as the client retries requests. If no reset time has been seen, the
+// client's backoff is used instead. This allows retrying a whole sequence of
+// calls rather than single requests.
+func (c *Client) DoWithRateLimitWait(ctx context.Context, f func() error) error {
+	for retries := 0; ; retries++ {
+		err := f()
+		if !IsError(err, ErrorCodeRateLimitExceeded) {
+			return err
+		}
+		if retries >= c.maxRetries {
+			return RetriesExceededError{Attempts: retries + 1, Err: err}
+		}
+
+		wait := c.backoffFunc(retries)
+		if reset := c.RateLimitResetAt(); reset.After(time.Now()) {
+			wait = time.Until(reset)
+		}
+		if c.retryCallback != nil {
+			c.retryCallback(retries+1, err, wait)
+		}
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// redactedAuthorization replaces the Authorization header value wherever a
+// request is serialized for logging.
+const redactedAuthorization = "Bearer [REDACTED]"
+
+// redactHeader returns a copy of h with the Authorization value masked.
+func redactHeader(h http.Header) http.Header {
+	h = h.Clone()
+	if h.Get("Authorization") != "" {
+		h.Set("Authorization", redactedAuthorization)
+	}
+	return h
+}
+
+// redactError masks the client's token in the message of err, in case the
+// error quotes request details.
+func (c *Client) redactError(err error) error {
+	if err == nil || c.token == "" || !strings.Contains(err.Error(), c.token) {
+		return err
+	}
+	return errors.New(strings.Replace(err.Error(), c.token, "[REDACTED]", -1))
+}
+
+// dumpRequest writes r to the debug writer, if any. The body is dumped from
+// a copy obtained through GetBody, so r can still be sent afterwards.
+func (c *Client) dumpRequest(r *http.Request) {
+	if c.debugWriter == nil {
+		return
+	}
+
+	dr := r.Clone(r.Context())
+	dr.Header = redactHeader(r.Header)
+	withBody := r.Body == nil
+	if r.GetBody != nil {
+		if b, err := r.GetBody(); err == nil {
+			dr.Body = b
+			withBody = true
+		}
+	}
+
+	dump, err := httputil.DumpRequestOut(dr, withBody)
+	if err != nil {
+		fmt.Fprintf(c.debugWriter, "hcloud: error dumping request: %s\n\n", err)
+		return
+	}
+	fmt.Fprintf(c.debugWriter, "%s\n\n", dump)
+}
+
+// dumpResponse writes resp to the debug writer, if any. The body of resp
+// must be re-readable, as DumpResponse consumes and replaces it.
+func (c *Client) dumpResponse(resp *http.Response) {
+	if c.debugWriter == nil {
+		return
+	}
+
+	dump, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		fmt.Fprintf(c.debugWriter, "hcloud: error dumping response: %s\n\n", err)
+		return
+	}
+	fmt.Fprintf(c.debugWriter, "%s\n\n", dump)
+}
+
+// applyTransportOptions applies the transport options to copies of the HTTP
+// client and its transport, so neither a caller's HTTP client nor
+// http.DefaultTransport is modified. It runs once all options have been
+// applied, so WithHTTPClient cannot drop them.
+func (c *Client) applyTransportOptions() {
+	if len(c.transportOptions) == 0 {
+		return
+	}
+	rt, ok := c.httpClientTransport().(*http.Transport)
+	if !ok {
+		if c.optionErr == nil {
+			c.optionErr = errors.New("hcloud: WithProxy and WithConnectionPool require the HTTP client to use an *http.Transport")
+		}
+		return
+	}
+
+	t := rt.Clone()
+	for _, option := range c.transportOptions {
+		option(t)
+	}
+	httpClient := *c.httpClient
+	httpClient.Transport = t
+	c.httpClient = &httpClient
+}
+
+// httpClientTransport returns the transport the HTTP client sends requests
+// with, which is http.DefaultTransport if none is set.
+func (c *Client) httpClientTransport() http.RoundTripper {
+	if c.httpClient == nil || c.httpClient.Transport == nil {
+		return http.DefaultTransport
+	}
+	return c.httpClient.Transport
+}
+
+// LastRateLimit returns the rate limit information of the most recent
+// response that carried it. It is safe for concurrent use.
+func (c *Client) LastRateLimit() Ratelimit {
+	c.rateLimitMu.Lock()
+	defer c.rateLimitMu.Unlock()
+	return c.lastRateLimit
+}
+
+// RateLimitResetAt returns the last seen point in time at which the rate
+// limit resets, or the zero time if no response has reported it yet.
+func (c *Client) RateLimitResetAt() time.Time {
+	return c.LastRateLimit().Reset
+}
+
+func (c *Client) setLastRateLimit(r Ratelimit) {
+	c.rateLimitMu.Lock()
+	defer c.rateLimitMu.Unlock()
+	c.lastRateLimit = r
+}
+
+// decode unmarshals the response body into v. With strict decoding, fields
+// unknown to v are an error, except for the top-level meta object, which is
+// read separately by readMeta.
+func (c *Client) decode(body []byte, v interface{}) error {
+	if !c.strictDecode && !c.useNumber {
+		return json.Unmarshal(body, v)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(c.stripMeta(body)))
+	if c.strictDecode {
+		dec.DisallowUnknownFields()
+	}
+	if c.useNumber {
+		dec.UseNumber()
+	}
+	return dec.Decode(v)
+}
+
+// stripMeta removes the top-level meta object from body when decoding
+// strictly, as it is read separately by readMeta.
+func (c *Client) stripMeta(body []byte) []byte {
+	if !c.strictDecode {
+		return body
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return body
+	}
+	if _, ok := fields["meta"]; !ok {
+		return body
+	}
+	delete(fields, "meta")
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return body
+	}
+	return b
+}
+
+// backoff waits before the next retry of a request that failed with err.
+// The wait is the backoff duration, shortened to hint if hasHint is set and
+// the API indicated an earlier point in time to retry at. If the wait would
+// run past the deadline of ctx, err is returned right away; ctx.Err() is
+// returned if ctx is done before the wait is over.
+func (c *Client) backoff(ctx context.Context, retries int, err error, hint time.Duration, hasHint bool) error {
+	wait := c.backoffFunc(retries)
+	if hasHint && hint > 0 && hint < wait {
+		wait = hint
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+		return err
+	}
+	if c.retryCallback != nil {
+		c.retryCallback(retries+1, err, wait)
+	}
+	return sleepContext(ctx, wait)
+}
+
+// sleepContext waits for d, returning ctx.Err() if ctx is done first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Each walks all pages of the list endpoint at path, calling fn with the
+// resource array of each page, e.g. the value of "servers" for /servers.
+// The filters in opts are kept for every page; opts.Page is ignored.
+// Iteration stops at the first error returned by fn.
+func (c *Client) Each(ctx context.Context, path string, opts ListOpts, fn func(json.RawMessage) error) error {
+	opts = allListOpts(opts)
+	_, err := c.all(func(page int) (*Response, error) {
+		opts.Page = page
+		req, err := c.NewRequestWithQuery(ctx, http.MethodGet, path, valuesForListOpts(opts), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var body map[string]json.RawMessage
+		resp, err := c.Do(req, &body)
+		if err != nil {
+			return resp, err
+		}
+		delete(body, "meta")
+		if len(body) != 1 {
+			return resp, fmt.Errorf("hcloud: expected a single resource array in response of %s, got %d fields", path, len(body))
+		}
+		for _, items := range body {
+			if err := fn(items); err != nil {
+				return resp, err
+			}
+		}
+		return resp, nil
+	})
+	return err
 }
 
 func (c *Client) all(f func(int) (*Response, error)) (*Response, error) {
@@ -212,8 +714,25 @@ func (c *Client) buildUserAgent() {
 	}
 }
 
-func errorFromResponse(resp *http.Response, body []byte) error {
-	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+// isJSONContentType reports whether the Content-Type header value h denotes
+// JSON. In strict mode only application/json is accepted, otherwise also
+// text/json and media types with a +json suffix.
+func isJSONContentType(h string, strict bool) bool {
+	mediaType, _, err := mime.ParseMediaType(h)
+	if err != nil {
+		return false
+	}
+	if mediaType == "application/json" {
+		return true
+	}
+	if strict {
+		return false
+	}
+	return mediaType == "text/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func errorFromResponse(resp *http.Response, body []byte, strict bool) error {
+	if !isJSONContentType(resp.Header.Get("Content-Type"), strict) {
 		return nil
 	}
 
@@ -233,7 +752,7 @@ type Response struct {
 	Meta Meta
 }
 
-func (r *Response) readMeta(body []byte) error {
+func (r *Response) readMeta(body []byte, strict bool) error {
 	if h := r.Header.Get("RateLimit-Limit"); h != "" {
 		r.Meta.Ratelimit.Limit, _ = strconv.Atoi(h)
 	}
@@ -246,7 +765,7 @@ func (r *Response) readMeta(body []byte) error {
 		}
 	}
 
-	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+	if isJSONContentType(r.Header.Get("Content-Type"), strict) {
 		var s models.MetaResponse
 		if err := json.Unmarshal(body, &s); err != nil {
 			return err
@@ -260,6 +779,37 @@ func (r *Response) readMeta(body []byte) error {
 	return nil
 }
 
+// retryAfter returns how long to wait before retrying as indicated by the
+// Retry-After header, in seconds or as an HTTP date. ok is false if the
+// header is not set, invalid or does not point to the future, so the
+// regular backoff applies.
+func (r *Response) retryAfter() (delay time.Duration, ok bool) {
+	h := r.Header.Get("Retry-After")
+	if h == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(h); err == nil {
+		delay = time.Duration(secs) * time.Second
+	} else if t, err := http.ParseTime(h); err == nil {
+		delay = time.Until(t)
+	}
+	return delay, delay > 0
+}
+
+// rateLimitDelay returns how long to wait before retrying a rate limited
+// request, as indicated by the Retry-After header and the rate limit reset
+// time. The sooner of both is used; hints in the past are ignored, and ok
+// is false if no usable hint is left.
+func (r *Response) rateLimitDelay() (delay time.Duration, ok bool) {
+	delay, ok = r.retryAfter()
+	if !r.Meta.Ratelimit.Reset.IsZero() {
+		if d := time.Until(r.Meta.Ratelimit.Reset); d > 0 && (!ok || d < delay) {
+			delay, ok = d, true
+		}
+	}
+	return delay, ok
+}
+
 // Meta represents meta information included in an API response.
 type Meta struct {
 	Pagination *Pagination
@@ -297,9 +847,23 @@ type Ratelimit struct {
 
 // ListOpts specifies options for listing resources.
 type ListOpts struct {
-	Page          int    // Page (starting at 1)
-	PerPage       int    // Items per page (0 means default)
-	LabelSelector string // Label selector for filtering by labels
+	Page          int        // Page (starting at 1)
+	PerPage       int        // Items per page (0 means default)
+	LabelSelector string     // Label selector for filtering by labels
+	Extra         url.Values // Additional query parameters not modelled by the library
+}
+
+// maxPerPage is the largest page size accepted by the API.
+const maxPerPage = 50
+
+// allListOpts returns opts as used by helpers that fetch all pages. Unless
+// the caller chose a page size, the largest one is requested to minimize
+// round trips.
+func allListOpts(opts ListOpts) ListOpts {
+	if opts.PerPage == 0 {
+		opts.PerPage = maxPerPage
+	}
+	return opts
 }
 
 func valuesForListOpts(opts ListOpts) url.Values {
@@ -313,6 +877,11 @@ func valuesForListOpts(opts ListOpts) url.Values {
 	if len(opts.LabelSelector) > 0 {
 		vals.Add("label_selector", opts.LabelSelector)
 	}
+	for k, vs := range opts.Extra {
+		for _, v := range vs {
+			vals.Add(k, v)
+		}
+	}
 	return vals
 }
 
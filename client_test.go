@@ -0,0 +1,670 @@
+package gohetz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client talking to an httptest server running
+// handler, without waiting between retries.
+func newTestClient(t *testing.T, handler http.HandlerFunc, options ...ClientOption) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	options = append([]ClientOption{
+		WithEndpoint(server.URL),
+		WithToken("token"),
+		WithBackoffFunc(ConstantBackoff(0)),
+	}, options...)
+	return NewClient(options...)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write([]byte(body))
+}
+
+func TestDoRetriesServerErrors(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			writeJSON(w, http.StatusServiceUnavailable, `{"error":{"code":"unavailable","message":"try again"}}`)
+			return
+		}
+		writeJSON(w, http.StatusOK, `{}`)
+	})
+
+	req, err := client.NewRequest(context.Background(), http.MethodGet, "/servers", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Do(req, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 requests, got %d", calls)
+	}
+}
+
+func TestDoDoesNotRetry(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		status int
+	}{
+		{"client error", http.MethodGet, http.StatusNotFound},
+		{"not implemented", http.MethodGet, http.StatusNotImplemented},
+		{"non-idempotent", http.MethodPost, http.StatusGatewayTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				writeJSON(w, tt.status, `{"error":{"code":"some_error","message":"failed"}}`)
+			})
+
+			req, err := client.NewRequest(context.Background(), tt.method, "/servers", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := client.Do(req, nil); !IsError(err, "some_error") {
+				t.Errorf("expected API error, got %v", err)
+			}
+			if calls != 1 {
+				t.Errorf("expected 1 request, got %d", calls)
+			}
+		})
+	}
+}
+
+func TestStrictDecode(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/servers/1":
+			writeJSON(w, http.StatusOK, `{"server":{"id":1,"name":"a","bogus_field":true}}`)
+		default:
+			writeJSON(w, http.StatusOK, `{"servers":[{"id":1,"name":"a"}],"meta":{"pagination":{"page":1}}}`)
+		}
+	}, WithStrictDecode())
+
+	if _, err := client.GetServer(context.Background(), 1); err == nil {
+		t.Error("expected error for unknown field")
+	}
+	if _, err := client.GetAllServers(context.Background(), ListOpts{}); err != nil {
+		t.Errorf("unexpected error for list with meta: %v", err)
+	}
+}
+
+func TestWithProxyDoesNotModifyCallerTransport(t *testing.T) {
+	httpClient := &http.Client{Transport: http.DefaultTransport}
+	client := NewClient(WithHTTPClient(httpClient), WithProxy("http://proxy.example.com:3128"))
+
+	if client.optionErr != nil {
+		t.Fatal(client.optionErr)
+	}
+	if client.httpClient.Transport == http.DefaultTransport {
+		t.Error("proxy was set on http.DefaultTransport")
+	}
+	if httpClient.Transport != http.DefaultTransport {
+		t.Error("caller's HTTP client was modified")
+	}
+	if client.httpClient == httpClient {
+		t.Error("expected the client to use a copy of the caller's HTTP client")
+	}
+}
+
+func TestWithProxySendsRequestsThroughProxy(t *testing.T) {
+	var proxied int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&proxied, 1)
+		if r.URL.Host != "api.example.com" || r.URL.Path != "/v1/servers/1" {
+			t.Errorf("expected a proxied request for api.example.com/v1/servers/1, got %s", r.URL)
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Error("expected the Authorization header to be passed on")
+		}
+		writeJSON(w, http.StatusOK, `{"server":{"id":1,"name":"a"}}`)
+	}))
+	defer proxy.Close()
+
+	client := NewClient(WithEndpoint("http://api.example.com/v1"), WithToken("token"), WithProxy(proxy.URL))
+	server, err := client.GetServer(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.Server.Name != "a" || proxied != 1 {
+		t.Errorf("expected server a through 1 proxied request, got %v after %d", server, proxied)
+	}
+}
+
+func TestWithProxyInvalidURL(t *testing.T) {
+	for _, proxyURL := range []string{"://proxy", "proxy.example.com:3128"} {
+		client := NewClient(WithProxy(proxyURL))
+		_, err := client.NewRequest(context.Background(), http.MethodGet, "/servers", nil)
+		if err == nil || !strings.Contains(err.Error(), "invalid proxy URL") {
+			t.Errorf("%s: expected the invalid proxy URL to be reported by the first request, got %v", proxyURL, err)
+		}
+	}
+}
+
+func TestWithHTTPClientIgnoresNil(t *testing.T) {
+	client := NewClient(WithHTTPClient(nil))
+	if client.httpClient == nil {
+		t.Fatal("expected the default HTTP client")
+	}
+
+	httpClient := &http.Client{Timeout: time.Second}
+	client = NewClient(WithHTTPClient(httpClient), WithHTTPClient(nil))
+	if client.httpClient != httpClient {
+		t.Error("expected a nil HTTP client not to replace the configured one")
+	}
+}
+
+func TestTransportOptionsApplyRegardlessOfOrder(t *testing.T) {
+	httpClient := &http.Client{Timeout: time.Second, Transport: &http.Transport{}}
+	tests := map[string][]ClientOption{
+		"before": {WithProxy("http://proxy.example.com:3128"), WithConnectionPool(10, 5, time.Minute), WithHTTPClient(httpClient)},
+		"after":  {WithHTTPClient(httpClient), WithProxy("http://proxy.example.com:3128"), WithConnectionPool(10, 5, time.Minute)},
+	}
+	for name, options := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := NewClient(options...)
+			if client.optionErr != nil {
+				t.Fatal(client.optionErr)
+			}
+			if client.httpClient.Timeout != time.Second {
+				t.Error("expected the caller's HTTP client settings to be kept")
+			}
+			tr, ok := client.httpClient.Transport.(*http.Transport)
+			if !ok || tr == httpClient.Transport {
+				t.Fatalf("expected a copy of the caller's transport, got %v", client.httpClient.Transport)
+			}
+			req, _ := http.NewRequest(http.MethodGet, "https://api.example.com", nil)
+			if u, err := tr.Proxy(req); err != nil || u == nil || u.Host != "proxy.example.com:3128" {
+				t.Errorf("expected the proxy to be set, got %v, %v", u, err)
+			}
+			if tr.MaxIdleConns != 10 || tr.MaxIdleConnsPerHost != 5 || tr.IdleConnTimeout != time.Minute {
+				t.Errorf("expected the connection pool to be set, got %d, %d, %s", tr.MaxIdleConns, tr.MaxIdleConnsPerHost, tr.IdleConnTimeout)
+			}
+		})
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestTransportOptionsRequireHTTPTransport(t *testing.T) {
+	client := NewClient(
+		WithHTTPClient(&http.Client{Transport: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return nil, errors.New("unexpected request")
+		})}),
+		WithConnectionPool(10, 5, time.Minute),
+	)
+	if _, err := client.NewRequest(context.Background(), http.MethodGet, "/servers", nil); err == nil {
+		t.Error("expected the connection pool on a custom transport to be reported")
+	}
+}
+
+func TestDoGivesUpOnRateLimit(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		writeJSON(w, http.StatusTooManyRequests, `{"error":{"code":"rate_limit_exceeded","message":"limit reached"}}`)
+	}, WithMaxRetries(2))
+
+	req, err := client.NewRequest(context.Background(), http.MethodGet, "/servers", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Do(req, nil)
+
+	var retriesErr RetriesExceededError
+	if !errors.As(err, &retriesErr) {
+		t.Fatalf("expected RetriesExceededError, got %v", err)
+	}
+	if retriesErr.Attempts != 3 || calls != 3 {
+		t.Errorf("expected 3 attempts, got %d with %d requests", retriesErr.Attempts, calls)
+	}
+	if !IsError(err, ErrorCodeRateLimitExceeded) {
+		t.Errorf("expected wrapped rate limit error, got %v", retriesErr.Err)
+	}
+}
+
+func TestLargeIDsRoundTrip(t *testing.T) {
+	const id = 9007199254740993 // 2^53+1, not representable as float64
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, `{"server":{"id":9007199254740993,"name":"a"}}`)
+	}, WithUseNumber())
+
+	server, err := client.GetServer(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.Server.ID != id {
+		t.Errorf("expected ID %d, got %d", int64(id), server.Server.ID)
+	}
+
+	req, err := client.NewRequest(context.Background(), http.MethodGet, "/servers/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]map[string]interface{}
+	if _, err := client.Do(req, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if n, ok := raw["server"]["id"].(json.Number); !ok || n.String() != "9007199254740993" {
+		t.Errorf("expected json.Number 9007199254740993, got %#v", raw["server"]["id"])
+	}
+}
+
+func TestDoIgnoresStaleRateLimitReset(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("RateLimit-Reset", strconv.FormatInt(time.Now().Add(-5*time.Second).Unix(), 10))
+			w.Header().Set("Retry-After", "0")
+			writeJSON(w, http.StatusTooManyRequests, `{"error":{"code":"rate_limit_exceeded","message":"limit reached"}}`)
+			return
+		}
+		writeJSON(w, http.StatusOK, `{}`)
+	}, WithBackoffFunc(ConstantBackoff(50*time.Millisecond)))
+
+	req, err := client.NewRequest(context.Background(), http.MethodGet, "/servers", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if _, err := client.Do(req, nil); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("expected the backoff to apply despite the stale reset, retried after %s", elapsed)
+	}
+}
+
+func TestDefaultBackoffVaries(t *testing.T) {
+	backoff := NewClient().backoffFunc
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 20; i++ {
+		wait := backoff(1)
+		if wait < time.Second || wait >= 1500*time.Millisecond {
+			t.Fatalf("backoff %s out of range [1s, 1.5s)", wait)
+		}
+		seen[wait] = true
+	}
+	if len(seen) < 2 {
+		t.Error("expected the default backoff to vary across calls")
+	}
+}
+
+func TestRetryCallbackCountsAttempts(t *testing.T) {
+	var (
+		calls    int32
+		attempts []int
+	)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 3 {
+			writeJSON(w, http.StatusTooManyRequests, `{"error":{"code":"rate_limit_exceeded","message":"limit reached"}}`)
+			return
+		}
+		writeJSON(w, http.StatusOK, `{}`)
+	}, WithBackoffFunc(ConstantBackoff(time.Millisecond)), WithRetryCallback(func(attempt int, err error, wait time.Duration) {
+		attempts = append(attempts, attempt)
+		if !IsError(err, ErrorCodeRateLimitExceeded) {
+			t.Errorf("attempt %d: expected rate limit error, got %v", attempt, err)
+		}
+		if wait != time.Millisecond {
+			t.Errorf("attempt %d: expected a wait of 1ms, got %s", attempt, wait)
+		}
+	}))
+
+	req, err := client.NewRequest(context.Background(), http.MethodGet, "/servers", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Do(req, nil); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(attempts, want) {
+		t.Errorf("expected attempts %v, got %v", want, attempts)
+	}
+}
+
+func TestDoWithRateLimitWaitWaitsForReset(t *testing.T) {
+	reset := time.Now().Add(time.Second).Truncate(time.Second).Add(time.Second)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		writeJSON(w, http.StatusOK, `{}`)
+	}, WithBackoffFunc(ConstantBackoff(time.Hour)))
+
+	var calls int
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := client.DoWithRateLimitWait(ctx, func() error {
+		calls++
+		if calls > 1 {
+			return nil
+		}
+		req, err := client.NewRequest(ctx, http.MethodGet, "/servers", nil)
+		if err != nil {
+			return err
+		}
+		if _, err := client.Do(req, nil); err != nil {
+			return err
+		}
+		return Error{Code: ErrorCodeRateLimitExceeded, Message: "limit reached"}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+	if now := time.Now(); now.Before(reset) {
+		t.Errorf("expected to wait until the reset at %s, returned at %s", reset, now)
+	}
+}
+
+func TestDoWithRateLimitWaitGivesUp(t *testing.T) {
+	client := NewClient(WithMaxRetries(2), WithBackoffFunc(ConstantBackoff(0)))
+
+	var calls int
+	err := client.DoWithRateLimitWait(context.Background(), func() error {
+		calls++
+		return Error{Code: ErrorCodeRateLimitExceeded, Message: "limit reached"}
+	})
+
+	var retriesErr RetriesExceededError
+	if !errors.As(err, &retriesErr) {
+		t.Fatalf("expected RetriesExceededError, got %v", err)
+	}
+	if retriesErr.Attempts != 3 || calls != 3 {
+		t.Errorf("expected 3 attempts, got %d with %d calls", retriesErr.Attempts, calls)
+	}
+}
+
+func TestDoReturnsErrorWhenBackoffExceedsDeadline(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		writeJSON(w, http.StatusServiceUnavailable, `{"error":{"code":"service_error","message":"unavailable"}}`)
+	}, WithBackoffFunc(ConstantBackoff(time.Hour)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	req, err := client.NewRequest(ctx, http.MethodGet, "/servers", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	_, err = client.Do(req, nil)
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected Do to return right away, took %s", elapsed)
+	}
+	if !IsError(err, ErrorCodeServiceError) {
+		t.Errorf("expected the service error rather than waiting for the deadline, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 request, got %d", calls)
+	}
+}
+
+func TestDoReturnsPromptlyWhenCancelledDuringBackoff(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusServiceUnavailable, `{"error":{"code":"service_error","message":"unavailable"}}`)
+	}, WithBackoffFunc(ConstantBackoff(time.Hour)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	req, err := client.NewRequest(ctx, http.MethodGet, "/servers", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	_, err = client.Do(req, nil)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected Do to return once cancelled, took %s", elapsed)
+	}
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestErrorContentType(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []ClientOption
+		want    bool
+	}{
+		{name: "default", want: true},
+		{name: "strict", options: []ClientOption{WithStrictContentType()}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/json; charset=utf-8")
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"error":{"code":"not_found","message":"server not found"}}`))
+			}, tt.options...)
+
+			req, err := client.NewRequest(context.Background(), http.MethodGet, "/servers/1", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = client.Do(req, nil)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if got := IsError(err, ErrorCodeNotFound); got != tt.want {
+				t.Errorf("expected the text/json error body to be parsed: %t, got error %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestNewJSONRequest(t *testing.T) {
+	client := NewClient(WithToken("token"))
+
+	req, err := client.NewJSONRequest(context.Background(), http.MethodPost, "/servers", struct {
+		Name   string `json:"name"`
+		Labels []int  `json:"labels,omitempty"`
+	}{Name: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct := req.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected Content-Type application/json, got %q", ct)
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != `{"name":"a"}` {
+		t.Errorf("expected the marshalled body, got %s", body)
+	}
+
+	req, err = client.NewJSONRequest(context.Background(), http.MethodPost, "/servers/1/actions/poweron", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Body != nil || req.ContentLength != 0 {
+		t.Errorf("expected no body, got %d bytes", req.ContentLength)
+	}
+	if ct := req.Header.Get("Content-Type"); ct != "" {
+		t.Errorf("expected no Content-Type without a body, got %q", ct)
+	}
+
+	if _, err := client.NewJSONRequest(context.Background(), http.MethodPost, "/servers", func() {}); err == nil {
+		t.Error("expected an error for a body that cannot be marshalled")
+	}
+}
+
+func TestExtraQueryParameters(t *testing.T) {
+	client := NewClient(WithEndpoint("https://api.example.com/v1"))
+
+	req, err := client.NewRequestWithQuery(context.Background(), http.MethodGet, "/servers?page=2", url.Values{"status": {"running", "off"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q := req.URL.Query(); q.Get("page") != "2" || !reflect.DeepEqual(q["status"], []string{"running", "off"}) {
+		t.Errorf("expected page and both status filters, got %s", req.URL)
+	}
+
+	var query url.Values
+	client = newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		writeJSON(w, http.StatusOK, `{"servers":[]}`)
+	})
+	_, err = client.GetAllServers(context.Background(), ListOpts{
+		LabelSelector: "env=prod",
+		Extra:         url.Values{"status": {"running"}, "sort": {"name:asc"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query.Get("status") != "running" || query.Get("sort") != "name:asc" || query.Get("label_selector") != "env=prod" {
+		t.Errorf("expected the extra parameters alongside the modelled ones, got %v", query)
+	}
+}
+
+func TestWithPathPrefix(t *testing.T) {
+	tests := []struct {
+		prefix string
+		want   string
+	}{
+		{prefix: "", want: "https://api.example.com/servers"},
+		{prefix: "/", want: "https://api.example.com/servers"},
+		{prefix: "v1", want: "https://api.example.com/v1/servers"},
+		{prefix: "/v1", want: "https://api.example.com/v1/servers"},
+		{prefix: "v1/", want: "https://api.example.com/v1/servers"},
+		{prefix: "/cloud/v1/", want: "https://api.example.com/cloud/v1/servers"},
+	}
+	for _, tt := range tests {
+		client := NewClient(WithEndpoint("https://api.example.com/"), WithPathPrefix(tt.prefix))
+		req, err := client.NewRequest(context.Background(), http.MethodGet, "/servers", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := req.URL.String(); got != tt.want {
+			t.Errorf("prefix %q: expected %s, got %s", tt.prefix, tt.want, got)
+		}
+	}
+}
+
+func TestRateLimitResetAt(t *testing.T) {
+	resets := []int64{1700000000, 1700000060}
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		if n <= int32(len(resets)) {
+			w.Header().Set("RateLimit-Limit", "3600")
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(3600-int(n)))
+			w.Header().Set("RateLimit-Reset", strconv.FormatInt(resets[n-1], 10))
+		}
+		writeJSON(w, http.StatusOK, `{}`)
+	})
+
+	if reset := client.RateLimitResetAt(); !reset.IsZero() {
+		t.Errorf("expected no reset before the first response, got %s", reset)
+	}
+	for i, want := range []int64{1700000000, 1700000060, 1700000060} {
+		req, err := client.NewRequest(context.Background(), http.MethodGet, "/servers", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.Do(req, nil); err != nil {
+			t.Fatal(err)
+		}
+		if reset := client.RateLimitResetAt(); !reset.Equal(time.Unix(want, 0)) {
+			t.Errorf("response %d: expected reset at %s, got %s", i+1, time.Unix(want, 0), reset)
+		}
+	}
+	if remaining := client.LastRateLimit().Remaining; remaining != 3598 {
+		t.Errorf("expected 3598 remaining requests from the latest header, got %d", remaining)
+	}
+}
+
+// requestRecorder is an http.Handler answering requests from a table of
+// responses keyed by "METHOD /path" and recording the requests it got.
+type requestRecorder struct {
+	t         *testing.T
+	responses map[string]func(w http.ResponseWriter, r *http.Request)
+
+	mu       sync.Mutex
+	requests []string
+	bodies   map[string][]byte
+}
+
+func newRequestRecorder(t *testing.T) *requestRecorder {
+	return &requestRecorder{
+		t:         t,
+		responses: make(map[string]func(w http.ResponseWriter, r *http.Request)),
+		bodies:    make(map[string][]byte),
+	}
+}
+
+// respond registers a JSON response for the given "METHOD /path" key.
+func (rr *requestRecorder) respond(key string, status int, body string) {
+	rr.responses[key] = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, status, body)
+	}
+}
+
+func (rr *requestRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := r.Method + " " + r.URL.Path
+	body, _ := ioutil.ReadAll(r.Body)
+
+	rr.mu.Lock()
+	rr.requests = append(rr.requests, key)
+	rr.bodies[key] = body
+	rr.mu.Unlock()
+
+	f, ok := rr.responses[key]
+	if !ok {
+		rr.t.Errorf("unexpected request %s", key)
+		writeJSON(w, http.StatusNotFound, `{"error":{"code":"not_found","message":"not found"}}`)
+		return
+	}
+	f(w, r)
+}
+
+// body returns the body of the last request for key, decoded into a map.
+func (rr *requestRecorder) body(key string) map[string]interface{} {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	var m map[string]interface{}
+	if err := json.Unmarshal(rr.bodies[key], &m); err != nil {
+		rr.t.Fatalf("decoding body of %s: %v", key, err)
+	}
+	return m
+}
+
+// recorded returns the requests received so far.
+func (rr *requestRecorder) recorded() []string {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	return append([]string(nil), rr.requests...)
+}
+
+// newRecorderClient returns a client talking to rr, polling actions without
+// delay.
+func newRecorderClient(t *testing.T, rr *requestRecorder, options ...ClientOption) *Client {
+	return newTestClient(t, rr.ServeHTTP, append([]ClientOption{WithPollInterval(0)}, options...)...)
+}
@@ -0,0 +1,44 @@
+package gohetz
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"./models"
+)
+
+const datacentersUrl = "/datacenters"
+
+// LocationForDatacenter returns the name of the location the datacenter
+// with the given name resides in.
+func (c *Client) LocationForDatacenter(ctx context.Context, dc string) (string, error) {
+	vals := url.Values{}
+	vals.Add("name", dc)
+	req, err := c.NewRequest(ctx, http.MethodGet, datacentersUrl+"?"+vals.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	raw, err := c.Do(req, nil)
+	if err != nil {
+		return "", err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(raw.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var datacenters models.Datacenters
+	if err := c.decode(bodyBytes, &datacenters); err != nil {
+		return "", err
+	}
+	if len(datacenters.Datacenters) == 0 {
+		return "", fmt.Errorf("hcloud: datacenter %q not found", dc)
+	}
+
+	return datacenters.Datacenters[0].Location.Name, nil
+}
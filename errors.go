@@ -1,6 +1,9 @@
 package gohetz
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 // ErrorCode represents an error code returned from the API.
 type ErrorCode string
@@ -12,6 +15,9 @@ const (
 	ErrorCodeUnknownError      ErrorCode = "unknown_error"       // Unknown error
 	ErrorCodeNotFound          ErrorCode = "not_found"           // Resource not found
 	ErrorCodeInvalidInput      ErrorCode = "invalid_input"       // Validation error
+	ErrorCodeConflict          ErrorCode = "conflict"            // Resource changed or is in use, e.g. volume already attached
+	ErrorCodeProtected         ErrorCode = "protected"           // Resource is protected against the operation
+	ErrorCodeUniquenessError   ErrorCode = "uniqueness_error"    // Resource with a unique attribute already exists
 
 	// Deprecated error codes
 
@@ -45,8 +51,38 @@ type ErrorDetailsInvalidInputField struct {
 	Messages []string
 }
 
-// IsError returns whether err is an API error with the given error code.
+// IsError returns whether err is, or wraps, an API error with the given error code.
 func IsError(err error, code ErrorCode) bool {
-	apiErr, ok := err.(Error)
-	return ok && apiErr.Code == code
+	var apiErr Error
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
+
+// IsConflict returns whether err is an API error reporting a conflict, e.g.
+// when attaching a volume that is already attached to another server.
+func IsConflict(err error) bool {
+	return IsError(err, ErrorCodeConflict)
+}
+
+// RetriesExceededError is returned when a request still fails after the
+// maximum number of retries. It wraps the error of the last attempt.
+type RetriesExceededError struct {
+	Attempts int
+	Err      error
+}
+
+func (e RetriesExceededError) Error() string {
+	return fmt.Sprintf("hcloud: giving up after %d attempts: %s", e.Attempts, e.Err)
+}
+
+// Unwrap returns the error of the last attempt.
+func (e RetriesExceededError) Unwrap() error {
+	return e.Err
+}
+
+// ErrNotSupported is returned by methods modelling operations the API does
+// not offer. Use errors.Is to check for it.
+var ErrNotSupported = errors.New("hcloud: operation not supported by the API")
+
+func notSupported(operation, reason string) error {
+	return fmt.Errorf("%w: %s: %s", ErrNotSupported, operation, reason)
 }
@@ -0,0 +1,139 @@
+package gohetz
+
+// serverFixture is a server as returned by the API, with every documented
+// field set.
+const serverFixture = `{
+	"backup_window": "22-02",
+	"created": "2016-01-30T23:55:00+00:00",
+	"datacenter": {
+		"description": "Falkenstein DC Park 8",
+		"id": 42,
+		"location": {
+			"city": "Falkenstein",
+			"country": "DE",
+			"description": "Falkenstein DC Park 1",
+			"id": 1,
+			"latitude": 50.47612,
+			"longitude": 12.370071,
+			"name": "fsn1",
+			"network_zone": "eu-central"
+		},
+		"name": "fsn1-dc8",
+		"server_types": {
+			"available": [1, 2, 3],
+			"available_for_migration": [1, 2, 3],
+			"supported": [1, 2, 3]
+		}
+	},
+	"id": 42,
+	"image": {
+		"architecture": "x86",
+		"bound_to": null,
+		"created": "2016-01-30T23:55:00+00:00",
+		"created_from": {"id": 1, "name": "Server"},
+		"deleted": null,
+		"deprecated": "2018-02-28T00:00:00+00:00",
+		"description": "Ubuntu 20.04 Standard 64 bit",
+		"disk_size": 10,
+		"id": 42,
+		"image_size": 2.3,
+		"labels": {"env": "dev"},
+		"name": "ubuntu-20.04",
+		"os_flavor": "ubuntu",
+		"os_version": "20.04",
+		"protection": {"delete": false},
+		"rapid_deploy": false,
+		"status": "available",
+		"type": "snapshot"
+	},
+	"included_traffic": 654321,
+	"ingoing_traffic": 123456,
+	"iso": {
+		"architecture": "x86",
+		"deprecated": "2018-02-28T00:00:00+00:00",
+		"deprecation": {
+			"announced": "2023-06-01T00:00:00+00:00",
+			"unavailable_after": "2023-09-01T00:00:00+00:00"
+		},
+		"description": "FreeBSD 11.0 x64",
+		"id": 42,
+		"name": "FreeBSD-11.0-RELEASE-amd64-dvd1",
+		"type": "public"
+	},
+	"labels": {"env": "dev"},
+	"load_balancers": [0],
+	"locked": false,
+	"name": "my-resource",
+	"outgoing_traffic": 123456,
+	"placement_group": {
+		"created": "2016-01-30T23:55:00+00:00",
+		"id": 42,
+		"labels": {"env": "dev"},
+		"name": "my-resource",
+		"servers": [42],
+		"type": "spread"
+	},
+	"primary_disk_size": 50,
+	"private_net": [
+		{
+			"alias_ips": [],
+			"ip": "10.0.0.2",
+			"mac_address": "86:00:ff:2a:7d:e1",
+			"network": 4711
+		}
+	],
+	"protection": {"delete": false, "rebuild": false},
+	"public_net": {
+		"firewalls": [{"id": 42, "status": "applied"}],
+		"floating_ips": [478],
+		"ipv4": {
+			"blocked": false,
+			"dns_ptr": "server01.example.com",
+			"id": 42,
+			"ip": "1.2.3.4"
+		},
+		"ipv6": {
+			"blocked": false,
+			"dns_ptr": [{"dns_ptr": "server.example.com", "ip": "2001:db8::1"}],
+			"id": 42,
+			"ip": "2001:db8::/64"
+		}
+	},
+	"rescue_enabled": false,
+	"server_type": {
+		"architecture": "x86",
+		"cores": 2,
+		"cpu_type": "shared",
+		"deprecated": false,
+		"deprecation": null,
+		"description": "CX22",
+		"disk": 40,
+		"id": 1,
+		"memory": 4,
+		"name": "cx22",
+		"prices": [
+			{
+				"included_traffic": 654321,
+				"location": "fsn1",
+				"price_hourly": {"gross": "1.1900000000000000", "net": "1.0000000000"},
+				"price_monthly": {"gross": "1.1900000000000000", "net": "1.0000000000"},
+				"price_per_tb_traffic": {"gross": "1.1900000000000000", "net": "1.0000000000"}
+			}
+		],
+		"storage_type": "local"
+	},
+	"status": "running",
+	"volumes": [0]
+}`
+
+// actionFixture is an action as returned by the API.
+const actionFixture = `{
+	"command": "create_server",
+	"error": null,
+	"finished": null,
+	"id": 1,
+	"progress": 0,
+	"resources": [{"id": 42, "type": "server"}],
+	"started": "2016-01-30T23:50:00+00:00",
+	"status": "running"
+}`
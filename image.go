@@ -0,0 +1,245 @@
+package gohetz
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"./models"
+)
+
+const imagesUrl = "/images/"
+
+// ImageListOpts specifies options for listing images.
+type ImageListOpts struct {
+	ListOpts
+	Type    []models.ImageType   // Only images of these types
+	BoundTo []int                // Only backups bound to these servers
+	Status  []models.ImageStatus // Only images with these statuses
+	Sort    []string             // Sort order, e.g. "created:desc"
+}
+
+func valuesForImageListOpts(opts ImageListOpts) url.Values {
+	vals := valuesForListOpts(opts.ListOpts)
+	for _, typ := range opts.Type {
+		vals.Add("type", string(typ))
+	}
+	for _, id := range opts.BoundTo {
+		vals.Add("bound_to", strconv.Itoa(id))
+	}
+	for _, status := range opts.Status {
+		vals.Add("status", string(status))
+	}
+	for _, sort := range opts.Sort {
+		vals.Add("sort", sort)
+	}
+	return vals
+}
+
+// ImageUpdateOpts specifies options for updating an image. Zero values are
+// left unchanged.
+type ImageUpdateOpts struct {
+	Description string            // New description
+	Type        models.ImageType  // Convert the image to this type (only snapshot)
+	Labels      map[string]string // New labels, replacing all existing ones
+}
+
+func (o ImageUpdateOpts) validate() error {
+	switch o.Type {
+	case "", models.Snapshot:
+		return nil
+	default:
+		return fmt.Errorf("hcloud: images can only be converted to %s, not %q", models.Snapshot, o.Type)
+	}
+}
+
+// GetAllImages returns all images matching opts, following pagination.
+// The filters in opts are kept for every page; opts.Page is ignored.
+func (c *Client) GetAllImages(ctx context.Context, opts ImageListOpts) (*models.Images, error) {
+	opts.ListOpts = allListOpts(opts.ListOpts)
+	images := &models.Images{}
+	_, err := c.all(func(page int) (*Response, error) {
+		opts.Page = page
+		p, resp, err := c.listImages(ctx, valuesForImageListOpts(opts))
+		if err != nil {
+			return resp, err
+		}
+		images.Images = append(images.Images, p.Images...)
+		return resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+// GetImage returns the image with the given ID. If the image does not exist,
+// nil is returned for both the image and the error.
+func (c *Client) GetImage(ctx context.Context, id int) (*models.ImageResponse, error) {
+	image, err := c.getImage(ctx, id)
+	if IsError(err, ErrorCodeNotFound) {
+		return nil, nil
+	}
+	return image, err
+}
+
+// GetImageByName returns the image with the given name, or nil if there is
+// none. Only system images have a name.
+func (c *Client) GetImageByName(ctx context.Context, name string) (*models.ImageResponse, error) {
+	vals := url.Values{}
+	vals.Add("name", name)
+	images, _, err := c.listImages(ctx, vals)
+	if err != nil {
+		return nil, err
+	}
+	if len(images.Images) == 0 {
+		return nil, nil
+	}
+	return &models.ImageResponse{Image: images.Images[0]}, nil
+}
+
+// UpdateImage updates the image with the given ID.
+func (c *Client) UpdateImage(ctx context.Context, id int, opts ImageUpdateOpts) (*models.ImageResponse, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	request := &models.ImageUpdateRequest{Type: opts.Type}
+	if opts.Description != "" {
+		request.Description = &opts.Description
+	}
+	if opts.Labels != nil {
+		labels := make(map[string]interface{}, len(opts.Labels))
+		for k, v := range opts.Labels {
+			labels[k] = v
+		}
+		request.Labels = &labels
+	}
+	req, err := c.NewJSONRequest(ctx, http.MethodPut, fmt.Sprintf("%s%d", imagesUrl, id), request)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := c.Do(req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(raw.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var image models.ImageResponse
+	if err := c.decode(bodyBytes, &image); err != nil {
+		return nil, err
+	}
+
+	return &image, nil
+}
+
+// DeleteImage deletes the image with the given ID. Only snapshots and
+// backups can be deleted; for system images the API error is returned.
+func (c *Client) DeleteImage(ctx context.Context, id int) error {
+	req, err := c.NewRequest(ctx, http.MethodDelete, fmt.Sprintf("%s%d", imagesUrl, id), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Do(req, nil)
+	return err
+}
+
+// ImageDeleteOpts specifies options for deleting an image.
+type ImageDeleteOpts struct {
+	// CheckType fetches the image first and refuses to delete it unless
+	// it is a snapshot or backup, guarding against a wrong ID.
+	CheckType bool
+}
+
+// DeleteImageWithOpts deletes the image with the given ID like DeleteImage,
+// with additional options.
+func (c *Client) DeleteImageWithOpts(ctx context.Context, id int, opts ImageDeleteOpts) error {
+	if opts.CheckType {
+		image, err := c.getImage(ctx, id)
+		if err != nil {
+			return err
+		}
+		switch image.Image.Type {
+		case models.Snapshot, models.Backup:
+		default:
+			return fmt.Errorf("hcloud: refusing to delete image %d of type %s, only snapshots and backups can be deleted", id, image.Image.Type)
+		}
+	}
+	return c.DeleteImage(ctx, id)
+}
+
+// ValidateImageForServerType returns an error if the image with the given ID
+// is built for a different CPU architecture than servers of the named type.
+func (c *Client) ValidateImageForServerType(ctx context.Context, imageID int, serverType string) error {
+	image, err := c.getImage(ctx, imageID)
+	if err != nil {
+		return err
+	}
+	st, err := c.getServerTypeByName(ctx, serverType)
+	if err != nil {
+		return err
+	}
+
+	if image.Image.Architecture != st.Architecture {
+		return fmt.Errorf("hcloud: image %d is built for %s, but server type %s is %s",
+			imageID, image.Image.Architecture, serverType, st.Architecture)
+	}
+	return nil
+}
+
+func (c *Client) getImage(ctx context.Context, id int) (*models.ImageResponse, error) {
+	req, err := c.NewRequest(ctx, http.MethodGet, fmt.Sprintf("%s%d", imagesUrl, id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := c.Do(req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(raw.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var image models.ImageResponse
+	if err := c.decode(bodyBytes, &image); err != nil {
+		return nil, err
+	}
+
+	return &image, nil
+}
+
+func (c *Client) listImages(ctx context.Context, vals url.Values) (*models.Images, *Response, error) {
+	req, err := c.NewRequest(ctx, http.MethodGet, imagesUrl+"?"+vals.Encode(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw, err := c.Do(req, nil)
+	if err != nil {
+		return nil, raw, err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(raw.Body)
+	if err != nil {
+		return nil, raw, err
+	}
+
+	var images models.Images
+	if err := c.decode(bodyBytes, &images); err != nil {
+		return nil, raw, err
+	}
+
+	return &images, raw, nil
+}
@@ -0,0 +1,67 @@
+package gohetz
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestDeleteImageWithOptsRejectsSystemImage(t *testing.T) {
+	rr := newRequestRecorder(t)
+	rr.respond("GET /images/3", http.StatusOK, `{"image":{"id":3,"name":"ubuntu-22.04","type":"system"}}`)
+	client := newRecorderClient(t, rr)
+
+	if err := client.DeleteImageWithOpts(context.Background(), 3, ImageDeleteOpts{CheckType: true}); err == nil {
+		t.Error("expected an error for a system image")
+	}
+	if want := []string{"GET /images/3"}; !reflect.DeepEqual(rr.recorded(), want) {
+		t.Errorf("expected only %v, got %v", want, rr.recorded())
+	}
+}
+
+func TestDeleteImageWithOptsDeletesSnapshot(t *testing.T) {
+	rr := newRequestRecorder(t)
+	rr.respond("GET /images/9", http.StatusOK, `{"image":{"id":9,"type":"snapshot"}}`)
+	rr.responses["DELETE /images/9"] = func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+	client := newRecorderClient(t, rr)
+
+	if err := client.DeleteImageWithOpts(context.Background(), 9, ImageDeleteOpts{CheckType: true}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"GET /images/9", "DELETE /images/9"}; !reflect.DeepEqual(rr.recorded(), want) {
+		t.Errorf("expected %v, got %v", want, rr.recorded())
+	}
+}
+
+func TestValidateImageForServerType(t *testing.T) {
+	tests := []struct {
+		name       string
+		serverType string
+		wantErr    bool
+	}{
+		{name: "match", serverType: "cx22"},
+		{name: "mismatch", serverType: "cax11", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := newRequestRecorder(t)
+			rr.respond("GET /images/3", http.StatusOK, `{"image":{"id":3,"name":"ubuntu-22.04","type":"system","architecture":"x86"}}`)
+			rr.responses["GET /server_types"] = func(w http.ResponseWriter, r *http.Request) {
+				architecture := "x86"
+				if r.URL.Query().Get("name") == "cax11" {
+					architecture = "arm"
+				}
+				writeJSON(w, http.StatusOK, `{"server_types":[{"id":1,"name":"`+r.URL.Query().Get("name")+`","architecture":"`+architecture+`"}]}`)
+			}
+			client := newRecorderClient(t, rr)
+
+			err := client.ValidateImageForServerType(context.Background(), 3, tt.serverType)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error: %t, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package gohetz
+
+import (
+	"context"
+	"time"
+
+	"./models"
+)
+
+const loadBalancersUrl = "/load_balancers/"
+
+// LBMetricType is the type of a load balancer metric.
+type LBMetricType string
+
+// Available load balancer metric types.
+const (
+	LBMetricOpenConnections      LBMetricType = "open_connections"
+	LBMetricConnectionsPerSecond LBMetricType = "connections_per_second"
+	LBMetricRequestsPerSecond    LBMetricType = "requests_per_second"
+	LBMetricBandwidth            LBMetricType = "bandwidth"
+)
+
+// LBMetricsOpts specifies options for fetching load balancer metrics.
+type LBMetricsOpts struct {
+	Types []LBMetricType // Metrics to fetch
+	Start time.Time      // Start of the period
+	End   time.Time      // End of the period
+	Step  int            // Resolution of the results in seconds (0 means default)
+}
+
+func (o LBMetricsOpts) query() metricsQuery {
+	q := metricsQuery{start: o.Start, end: o.End, step: o.Step}
+	for _, t := range o.Types {
+		q.types = append(q.types, string(t))
+	}
+	return q
+}
+
+// GetLoadBalancerMetrics returns the metrics of the load balancer with the given ID.
+func (c *Client) GetLoadBalancerMetrics(ctx context.Context, id int, opts LBMetricsOpts) (*models.LoadBalancerMetrics, error) {
+	var metrics models.LoadBalancerMetrics
+	if err := c.getMetrics(ctx, loadBalancersUrl, id, opts.query(), &metrics); err != nil {
+		return nil, err
+	}
+	return &metrics, nil
+}
@@ -0,0 +1,72 @@
+package gohetz
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const loadBalancerMetricsFixture = `{
+	"metrics": {
+		"start": "2017-01-01T00:00:00+00:00",
+		"end": "2017-01-01T23:00:00+00:00",
+		"step": 60,
+		"time_series": {
+			"open_connections": {
+				"values": [[1435781470.622, "42"], [1435781471.622, "43.5"]]
+			},
+			"requests_per_second": {
+				"values": [[1435781470.622, "0.000000000000000000001"]]
+			}
+		}
+	}
+}`
+
+func TestGetLoadBalancerMetrics(t *testing.T) {
+	start := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(23 * time.Hour)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/load_balancers/1/metrics" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if !reflect.DeepEqual(q["type"], []string{"open_connections", "requests_per_second"}) ||
+			q.Get("start") != "2017-01-01T00:00:00Z" || q.Get("end") != "2017-01-01T23:00:00Z" || q.Get("step") != "60" {
+			t.Errorf("unexpected query %v", q)
+		}
+		writeJSON(w, http.StatusOK, loadBalancerMetricsFixture)
+	}, WithStrictDecode())
+
+	metrics, err := client.GetLoadBalancerMetrics(context.Background(), 1, LBMetricsOpts{
+		Types: []LBMetricType{LBMetricOpenConnections, LBMetricRequestsPerSecond},
+		Start: start,
+		End:   end,
+		Step:  60,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metrics.Metrics.Step != 60 || metrics.Metrics.Start != "2017-01-01T00:00:00+00:00" {
+		t.Errorf("unexpected period %+v", metrics.Metrics)
+	}
+	open := metrics.Metrics.TimeSeries["open_connections"].Values
+	if len(open) != 2 || open[0].Timestamp != 1435781470.622 || open[0].Value != "42" || open[1].Value != "43.5" {
+		t.Errorf("unexpected open_connections values %+v", open)
+	}
+	rps := metrics.Metrics.TimeSeries["requests_per_second"].Values
+	if len(rps) != 1 || rps[0].Value != "0.000000000000000000001" {
+		t.Errorf("expected the value string to be kept as is, got %+v", rps)
+	}
+}
+
+func TestGetLoadBalancerMetricsRequiresTypes(t *testing.T) {
+	rr := newRequestRecorder(t)
+	client := newRecorderClient(t, rr)
+
+	_, err := client.GetLoadBalancerMetrics(context.Background(), 1, LBMetricsOpts{Start: time.Now().Add(-time.Hour), End: time.Now()})
+	if err == nil {
+		t.Error("expected an error without metric types")
+	}
+}
@@ -0,0 +1,73 @@
+package gohetz
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"./models"
+)
+
+// MetadataEndpoint is the URL of the metadata service, reachable from
+// within servers only.
+const MetadataEndpoint = "http://169.254.169.254/hetzner/v1/metadata"
+
+// FetchServerMetadata reads the metadata of the server it runs on from the
+// metadata service at metadataURL, or MetadataEndpoint if it is empty.
+func FetchServerMetadata(ctx context.Context, metadataURL string) (*models.Metadata, error) {
+	if metadataURL == "" {
+		metadataURL = MetadataEndpoint
+	}
+	metadataURL = strings.TrimRight(metadataURL, "/")
+
+	instanceID, err := fetchMetadataValue(ctx, metadataURL, "instance-id")
+	if err != nil {
+		return nil, err
+	}
+	hostname, err := fetchMetadataValue(ctx, metadataURL, "hostname")
+	if err != nil {
+		return nil, err
+	}
+	publicIPv4, err := fetchMetadataValue(ctx, metadataURL, "public-ipv4")
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.ParseInt(instanceID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("hcloud: invalid instance-id %q in metadata", instanceID)
+	}
+
+	return &models.Metadata{
+		InstanceID: id,
+		Hostname:   hostname,
+		PublicIPv4: publicIPv4,
+	}, nil
+}
+
+func fetchMetadataValue(ctx context.Context, metadataURL, key string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, metadataURL+"/"+key, nil)
+	if err != nil {
+		return "", err
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("hcloud: metadata service responded with status code %d for %s", resp.StatusCode, key)
+	}
+
+	return strings.TrimSpace(string(body)), nil
+}
@@ -0,0 +1,59 @@
+package gohetz
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newMetadataServer(t *testing.T, values map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		value, ok := values[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(value))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFetchServerMetadata(t *testing.T) {
+	server := newMetadataServer(t, map[string]string{
+		"/hetzner/v1/metadata/instance-id": "9007199254740993\n",
+		"/hetzner/v1/metadata/hostname":    "web-1",
+		"/hetzner/v1/metadata/public-ipv4": "203.0.113.7\n",
+	})
+
+	metadata, err := FetchServerMetadata(context.Background(), server.URL+"/hetzner/v1/metadata/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata.InstanceID != 9007199254740993 || metadata.Hostname != "web-1" || metadata.PublicIPv4 != "203.0.113.7" {
+		t.Errorf("unexpected metadata %+v", metadata)
+	}
+}
+
+func TestFetchServerMetadataErrors(t *testing.T) {
+	tests := map[string]map[string]string{
+		"missing key": {
+			"/instance-id": "42",
+		},
+		"invalid instance-id": {
+			"/instance-id": "web-1",
+			"/hostname":    "web-1",
+			"/public-ipv4": "203.0.113.7",
+		},
+	}
+	for name, values := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := newMetadataServer(t, values)
+			if _, err := FetchServerMetadata(context.Background(), server.URL); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package gohetz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// metricsQuery holds the options shared by the metrics endpoints of all
+// resource types.
+type metricsQuery struct {
+	types []string
+	start time.Time
+	end   time.Time
+	step  int
+}
+
+func (q metricsQuery) validate() error {
+	if len(q.types) == 0 {
+		return errors.New("hcloud: at least one metric type is required")
+	}
+	if q.start.IsZero() || q.end.IsZero() {
+		return errors.New("hcloud: start and end are required")
+	}
+	return nil
+}
+
+func (q metricsQuery) values() url.Values {
+	vals := url.Values{}
+	for _, t := range q.types {
+		vals.Add("type", t)
+	}
+	vals.Add("start", q.start.Format(time.RFC3339))
+	vals.Add("end", q.end.Format(time.RFC3339))
+	if q.step > 0 {
+		vals.Add("step", strconv.Itoa(q.step))
+	}
+	return vals
+}
+
+// getMetrics fetches the metrics of the resource with the given ID in the
+// collection at collectionUrl and decodes them into v.
+func (c *Client) getMetrics(ctx context.Context, collectionUrl string, id int, q metricsQuery, v interface{}) error {
+	if err := q.validate(); err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("%s%d/metrics?%s", collectionUrl, id, q.values().Encode())
+	req, err := c.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+
+	raw, err := c.Do(req, nil)
+	if err != nil {
+		return err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(raw.Body)
+	if err != nil {
+		return err
+	}
+
+	return c.decode(bodyBytes, v)
+}
@@ -17,8 +17,14 @@ func (r *Actions) Marshal() ([]byte, error) {
 	return json.Marshal(r)
 }
 
+func UnmarshalAction(data []byte) (Action, error) {
+	var r Action
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
 type Actions struct {
-	Actions []Action `json:"actions"`
+	Actions []ActionClass `json:"actions"`
 }
 
 type Action struct {
@@ -29,7 +35,7 @@ type ActionClass struct {
 	Command   string      `json:"command"`   // Command executed in the action
 	Error     *ErrorClass `json:"error"`     // Error message for the action if error occured, otherwise null.
 	Finished  *string     `json:"finished"`  // Point in time when the action was finished (in ISO-8601 format). Only set if the action; is finished otherwise null.
-	ID        float64     `json:"id"`        // ID of the action
+	ID        int64       `json:"id"`        // ID of the action
 	Progress  float64     `json:"progress"`  // Progress of action in percent
 	Resources []Resource  `json:"resources"` // Resources the action relates to
 	Started   string      `json:"started"`   // Point in time when the action was started (in ISO-8601 format)
@@ -42,8 +48,8 @@ type ErrorClass struct {
 }
 
 type Resource struct {
-	ID   float64 `json:"id"`   // ID of resource referenced
-	Type string  `json:"type"` // Type of resource referenced
+	ID   int64  `json:"id"`   // ID of resource referenced
+	Type string `json:"type"` // Type of resource referenced
 }
 
 // Status of the action
@@ -54,3 +60,16 @@ const (
 	StatusRunning Status = "running"
 	StatusSuccess Status = "success"
 )
+
+// ProgressPercent returns the progress of the action as a whole percentage,
+// clamped to the range 0-100.
+func (a *ActionClass) ProgressPercent() int {
+	switch {
+	case a.Progress <= 0:
+		return 0
+	case a.Progress >= 100:
+		return 100
+	default:
+		return int(a.Progress)
+	}
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// Amount is a monetary amount as returned by the API, e.g. "0.0079000000".
+// The raw string is kept alongside its exact rational value, so no precision
+// is lost to float parsing.
+type Amount struct {
+	Raw   string   // Amount as sent by the API
+	Value *big.Rat // Exact value of the amount, nil if Raw is empty
+}
+
+// UnmarshalJSON decodes an amount from its JSON string representation.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	a.Raw = raw
+	a.Value = nil
+	if raw == "" {
+		return nil
+	}
+	value, ok := new(big.Rat).SetString(raw)
+	if !ok {
+		return fmt.Errorf("invalid amount %q", raw)
+	}
+	a.Value = value
+	return nil
+}
+
+// MarshalJSON encodes the amount as the raw string it was decoded from.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.Raw)
+}
+
+// Float64 returns the nearest float64 value of the amount, or 0 if unset.
+func (a Amount) Float64() float64 {
+	if a.Value == nil {
+		return 0
+	}
+	f, _ := a.Value.Float64()
+	return f
+}
+
+// String returns the amount as sent by the API.
+func (a Amount) String() string {
+	return a.Raw
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestAmountKeepsPrecision(t *testing.T) {
+	const raw = "0.0079000000000000000001"
+
+	var price PriceHourly
+	if err := json.Unmarshal([]byte(`{"gross":"`+raw+`","net":"0.0066"}`), &price); err != nil {
+		t.Fatal(err)
+	}
+	if price.Gross.Raw != raw || price.Gross.String() != raw {
+		t.Errorf("expected raw amount %s, got %q", raw, price.Gross.Raw)
+	}
+	want, _ := new(big.Rat).SetString("79000000000000000001/10000000000000000000000")
+	if price.Gross.Value == nil || price.Gross.Value.Cmp(want) != 0 {
+		t.Errorf("expected exact value %s, got %v", want.FloatString(22), price.Gross.Value)
+	}
+	if f := price.Gross.Float64(); f != 0.0079 {
+		t.Errorf("expected float64 0.0079, got %v", f)
+	}
+
+	b, err := json.Marshal(price)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(b); got != `{"gross":"`+raw+`","net":"0.0066"}` {
+		t.Errorf("expected the raw amounts to be marshalled back, got %s", got)
+	}
+}
+
+func TestAmountEmptyAndInvalid(t *testing.T) {
+	var a Amount
+	if err := json.Unmarshal([]byte(`""`), &a); err != nil {
+		t.Fatal(err)
+	}
+	if a.Value != nil || a.Float64() != 0 {
+		t.Errorf("expected an unset amount, got %v", a.Value)
+	}
+	if err := json.Unmarshal([]byte(`"1.2.3"`), &a); err == nil {
+		t.Error("expected an error for an invalid amount")
+	}
+}
@@ -0,0 +1,16 @@
+// To parse and unparse this JSON data, add this code to your project and do:
+//
+//    bytes, err = changeProtectionRequest.Marshal()
+
+package models
+
+import "encoding/json"
+
+func (r *ChangeProtectionRequest) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type ChangeProtectionRequest struct {
+	Delete  *bool `json:"delete,omitempty"`  // If true, prevents the resource from being deleted
+	Rebuild *bool `json:"rebuild,omitempty"` // If true, prevents the server from being rebuilt. Servers only.
+}
@@ -0,0 +1,22 @@
+// To parse and unparse this JSON data, add this code to your project and do:
+//
+//    datacenters, err := UnmarshalDatacenters(bytes)
+//    bytes, err = datacenters.Marshal()
+
+package models
+
+import "encoding/json"
+
+func UnmarshalDatacenters(data []byte) (Datacenters, error) {
+	var r Datacenters
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *Datacenters) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type Datacenters struct {
+	Datacenters []Datacenter `json:"datacenters"`
+}
@@ -0,0 +1,47 @@
+// To parse and unparse this JSON data, add this code to your project and do:
+//
+//    imageResponse, err := UnmarshalImageResponse(bytes)
+//    images, err := UnmarshalImages(bytes)
+//    bytes, err = imageResponse.Marshal()
+
+package models
+
+import "encoding/json"
+
+func UnmarshalImageResponse(data []byte) (ImageResponse, error) {
+	var r ImageResponse
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *ImageResponse) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type ImageResponse struct {
+	Image Image `json:"image"`
+}
+
+func UnmarshalImages(data []byte) (Images, error) {
+	var r Images
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *Images) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type Images struct {
+	Images []Image `json:"images"`
+}
+
+func (r *ImageUpdateRequest) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type ImageUpdateRequest struct {
+	Description *string                 `json:"description,omitempty"` // New description of the image
+	Labels      *map[string]interface{} `json:"labels,omitempty"`      // New labels, replacing all existing ones
+	Type        ImageType               `json:"type,omitempty"`        // Destination image type to convert to (only snapshot)
+}
@@ -0,0 +1,22 @@
+// To parse and unparse this JSON data, add this code to your project and do:
+//
+//    loadBalancerMetrics, err := UnmarshalLoadBalancerMetrics(bytes)
+//    bytes, err = loadBalancerMetrics.Marshal()
+
+package models
+
+import "encoding/json"
+
+func UnmarshalLoadBalancerMetrics(data []byte) (LoadBalancerMetrics, error) {
+	var r LoadBalancerMetrics
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *LoadBalancerMetrics) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type LoadBalancerMetrics struct {
+	Metrics Metrics `json:"metrics"`
+}
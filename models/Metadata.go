@@ -0,0 +1,9 @@
+package models
+
+// Metadata defines the information a server can read about itself from the
+// metadata service.
+type Metadata struct {
+	InstanceID int64  // ID of the server
+	Hostname   string // Hostname of the server
+	PublicIPv4 string // Public IPv4 address of the server, empty if it has none
+}
@@ -0,0 +1,64 @@
+// To parse and unparse this JSON data, add this code to your project and do:
+//
+//    sshKeys, err := UnmarshalSSHKeys(bytes)
+//    sshKey, err := UnmarshalSSHKey(bytes)
+//    bytes, err = sshKeyCreateRequest.Marshal()
+
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+func UnmarshalSSHKeys(data []byte) (SSHKeys, error) {
+	var r SSHKeys
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *SSHKeys) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type SSHKeys struct {
+	SSHKeys []SSHKeyClass `json:"ssh_keys"`
+}
+
+func UnmarshalSSHKey(data []byte) (SSHKey, error) {
+	var r SSHKey
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+type SSHKey struct {
+	SSHKey SSHKeyClass `json:"ssh_key"`
+}
+
+type SSHKeyClass struct {
+	Created     time.Time              `json:"created"`     // Point in time when the SSH key was created
+	Fingerprint string                 `json:"fingerprint"` // Fingerprint of public key
+	ID          int64                  `json:"id"`          // ID of the SSH key
+	Labels      map[string]interface{} `json:"labels"`      // User-defined labels (key-value pairs)
+	Name        string                 `json:"name"`        // Name of the SSH key (must be unique per project)
+	PublicKey   string                 `json:"public_key"`  // Public key
+}
+
+func (r *SSHKeyCreateRequest) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type SSHKeyCreateRequest struct {
+	Labels    map[string]interface{} `json:"labels,omitempty"` // User-defined labels (key-value pairs)
+	Name      string                 `json:"name"`             // Name of the SSH key
+	PublicKey string                 `json:"public_key"`       // Public key
+}
+
+func (r *SSHKeyUpdateRequest) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type SSHKeyUpdateRequest struct {
+	Labels *map[string]interface{} `json:"labels,omitempty"` // New labels, replacing all existing ones. Nil leaves them unchanged, an empty map removes all.
+	Name   *string                 `json:"name,omitempty"`   // New name to set
+}
@@ -5,7 +5,10 @@
 
 package models
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+)
 
 func UnmarshalServers(data []byte) (Servers, error) {
 	var r Servers
@@ -32,30 +35,108 @@ type Server struct {
 }
 
 type ServerClass struct {
-	BackupWindow    *string                `json:"backup_window"`    // Time window (UTC) in which the backup will run, or null if the backups are not enabled
-	Created         string                 `json:"created"`          // Point in time when the server was created (in ISO-8601 format)
-	Datacenter      Datacenter             `json:"datacenter"`       // Datacenter this server is located at
-	ID              float64                `json:"id"`               // ID of server
-	Image           *Image                 `json:"image"`            // Image this server was created from.
-	IncludedTraffic float64                `json:"included_traffic"` // Free Traffic for the current billing period in bytes
-	IngoingTraffic  *float64               `json:"ingoing_traffic"`  // Inbound Traffic for the current billing period in bytes
-	ISO             *ISO                   `json:"iso"`              // ISO image that is attached to this server. Null if no ISO is attached.
-	Labels          map[string]interface{} `json:"labels"`           // User-defined labels (key-value pairs)
-	Locked          bool                   `json:"locked"`           // True if server has been locked and is not available to user.
-	Name            string                 `json:"name"`             // Name of the server (must be unique per project and a valid hostname as per RFC 1123)
-	OutgoingTraffic *float64               `json:"outgoing_traffic"` // Outbound Traffic for the current billing period in bytes
-	Protection      ServerProtection       `json:"protection"`       // Protection configuration for the server
-	PublicNet       PublicNet              `json:"public_net"`       // Public network information. The servers ipv4 address can be found in; `public_net->ipv4->ip`
-	RescueEnabled   bool                   `json:"rescue_enabled"`   // True if rescue mode is enabled: Server will then boot into rescue system on next reboot.
-	ServerType      ServerType             `json:"server_type"`      // Type of server - determines how much ram, disk and cpu a server has
-	Status          ServerStatus           `json:"status"`           // Status of the server
-	Volumes         []interface{}          `json:"volumes"`          // IDs of Volumes assigned to this server.
+	BackupWindow    *string                `json:"backup_window"`     // Time window (UTC) in which the backup will run, or null if the backups are not enabled
+	Created         time.Time              `json:"created"`           // Point in time when the server was created
+	Datacenter      Datacenter             `json:"datacenter"`        // Datacenter this server is located at
+	ID              int64                  `json:"id"`                // ID of server
+	Image           *Image                 `json:"image"`             // Image this server was created from.
+	IncludedTraffic *uint64                `json:"included_traffic"`  // Free Traffic for the current billing period in bytes
+	IngoingTraffic  *uint64                `json:"ingoing_traffic"`   // Inbound Traffic for the current billing period in bytes
+	ISO             *ISO                   `json:"iso"`               // ISO image that is attached to this server. Null if no ISO is attached.
+	Labels          map[string]interface{} `json:"labels"`            // User-defined labels (key-value pairs)
+	LoadBalancers   []int64                `json:"load_balancers"`    // IDs of load balancers the server is a target of
+	Locked          bool                   `json:"locked"`            // True if server has been locked and is not available to user.
+	Name            string                 `json:"name"`              // Name of the server (must be unique per project and a valid hostname as per RFC 1123)
+	OutgoingTraffic *uint64                `json:"outgoing_traffic"`  // Outbound Traffic for the current billing period in bytes
+	PlacementGroup  *PlacementGroup        `json:"placement_group"`   // Placement group the server is assigned to, or null if it is not in one
+	PrimaryDiskSize float64                `json:"primary_disk_size"` // Size of the primary disk in GB
+	PrivateNet      []PrivateNet           `json:"private_net"`       // Private networks the server is attached to
+	Protection      ServerProtection       `json:"protection"`        // Protection configuration for the server
+	PublicNet       PublicNet              `json:"public_net"`        // Public network information. The servers ipv4 address can be found in; `public_net->ipv4->ip`
+	RescueEnabled   bool                   `json:"rescue_enabled"`    // True if rescue mode is enabled: Server will then boot into rescue system on next reboot.
+	ServerType      ServerType             `json:"server_type"`       // Type of server - determines how much ram, disk and cpu a server has
+	Status          ServerStatus           `json:"status"`            // Status of the server
+	Volumes         []interface{}          `json:"volumes"`           // IDs of Volumes assigned to this server.
+}
+
+// Age returns how long ago the server was created.
+func (s *ServerClass) Age() time.Duration {
+	return time.Since(s.Created)
+}
+
+// PrimaryDiskSizeGB returns the size of the primary disk in whole GB. The
+// API reports whole numbers, so nothing is lost to the conversion.
+func (s *ServerClass) PrimaryDiskSizeGB() int {
+	return int(s.PrimaryDiskSize)
+}
+
+// OutgoingTrafficBytes returns the outbound traffic for the current billing
+// period in bytes, or 0 if the API has not reported any yet.
+func (s *ServerClass) OutgoingTrafficBytes() uint64 {
+	if s.OutgoingTraffic == nil {
+		return 0
+	}
+	return *s.OutgoingTraffic
+}
+
+// IngoingTrafficBytes returns the inbound traffic for the current billing
+// period in bytes, or 0 if the API has not reported any yet.
+func (s *ServerClass) IngoingTrafficBytes() uint64 {
+	if s.IngoingTraffic == nil {
+		return 0
+	}
+	return *s.IngoingTraffic
+}
+
+// IncludedTrafficBytes returns the free traffic for the current billing
+// period in bytes, or 0 if the API has not reported it.
+func (s *ServerClass) IncludedTrafficBytes() uint64 {
+	if s.IncludedTraffic == nil {
+		return 0
+	}
+	return *s.IncludedTraffic
+}
+
+// AttachedISO returns the ISO attached to the server, or nil if none is attached.
+func (s *ServerClass) AttachedISO() *ISO {
+	return s.ISO
+}
+
+// PlacementGroupID returns the ID of the server's placement group, or 0 if
+// it is not in one.
+func (s *ServerClass) PlacementGroupID() int64 {
+	if s.PlacementGroup == nil {
+		return 0
+	}
+	return s.PlacementGroup.ID
+}
+
+// DatacenterName returns the name of the datacenter the server is located
+// at, e.g. "fsn1-dc14".
+func (s *ServerClass) DatacenterName() string {
+	return s.Datacenter.Name
+}
+
+// LocationName returns the name of the location of the server's datacenter,
+// e.g. "fsn1".
+func (s *ServerClass) LocationName() string {
+	return s.Datacenter.Location.Name
+}
+
+// Placement group the server is assigned to
+type PlacementGroup struct {
+	Created time.Time              `json:"created"` // Point in time when the placement group was created
+	ID      int64                  `json:"id"`      // ID of the placement group
+	Labels  map[string]interface{} `json:"labels"`  // User-defined labels (key-value pairs)
+	Name    string                 `json:"name"`    // Name of the placement group
+	Servers []int64                `json:"servers"` // IDs of the servers assigned to the placement group
+	Type    string                 `json:"type"`    // Type of the placement group, e.g. spread
 }
 
 // Datacenter this server is located at
 type Datacenter struct {
 	Description string      `json:"description"`  // Description of the datacenter
-	ID          float64     `json:"id"`           // ID of the datacenter
+	ID          int64       `json:"id"`           // ID of the datacenter
 	Location    Location    `json:"location"`     // Location where the datacenter resides in
 	Name        string      `json:"name"`         // Unique identifier of the datacenter
 	ServerTypes ServerTypes `json:"server_types"` // The server types the datacenter can handle
@@ -63,51 +144,57 @@ type Datacenter struct {
 
 // Location where the datacenter resides in
 type Location struct {
-	City        string  `json:"city"`        // City the location is closest to
-	Country     string  `json:"country"`     // ISO 3166-1 alpha-2 code of the country the location resides in
-	Description string  `json:"description"` // Description of the location
-	ID          float64 `json:"id"`          // ID of the location
-	Latitude    float64 `json:"latitude"`    // Latitude of the city closest to the location
-	Longitude   float64 `json:"longitude"`   // Longitude of the city closest to the location
-	Name        string  `json:"name"`        // Unique identifier of the location
+	City        string  `json:"city"`         // City the location is closest to
+	Country     string  `json:"country"`      // ISO 3166-1 alpha-2 code of the country the location resides in
+	Description string  `json:"description"`  // Description of the location
+	ID          int64   `json:"id"`           // ID of the location
+	Latitude    float64 `json:"latitude"`     // Latitude of the city closest to the location
+	Longitude   float64 `json:"longitude"`    // Longitude of the city closest to the location
+	Name        string  `json:"name"`         // Unique identifier of the location
+	NetworkZone string  `json:"network_zone"` // Name of the network zone the location is part of
 }
 
 // The server types the datacenter can handle
 type ServerTypes struct {
-	Available []float64 `json:"available"` // IDs of server types that are supported and for which the datacenter has enough resources; left
-	Supported []float64 `json:"supported"` // IDs of server types that are supported in the datacenter
+	Available             []int64 `json:"available"`               // IDs of server types that are supported and for which the datacenter has enough resources; left
+	AvailableForMigration []int64 `json:"available_for_migration"` // IDs of server types a server in the datacenter can be migrated to
+	Supported             []int64 `json:"supported"`               // IDs of server types that are supported in the datacenter
 }
 
 type ISO struct {
-	Deprecated  *string `json:"deprecated"`  // ISO 8601 timestamp of deprecation, null if ISO is still available. After the deprecation; time it will no longer be possible to attach the ISO to servers.
-	Description string  `json:"description"` // Description of the ISO
-	ID          float64 `json:"id"`          // ID of the ISO
-	Name        *string `json:"name"`        // Unique identifier of the ISO. Only set for public ISOs
-	Type        ISOType `json:"type"`        // Type of the ISO
+	Architecture *Architecture `json:"architecture"` // CPU architecture the ISO is built for, or null if it is compatible with all
+	Deprecated   *string       `json:"deprecated"`   // ISO 8601 timestamp of deprecation, null if ISO is still available. After the deprecation; time it will no longer be possible to attach the ISO to servers.
+	Deprecation  *Deprecation  `json:"deprecation"`  // Deprecation details of the ISO, or null if it is not deprecated
+	Description  string        `json:"description"`  // Description of the ISO
+	ID           int64         `json:"id"`           // ID of the ISO
+	Name         *string       `json:"name"`         // Unique identifier of the ISO. Only set for public ISOs
+	Type         ISOType       `json:"type"`         // Type of the ISO
 }
 
 type Image struct {
-	BoundTo     *float64               `json:"bound_to"`               // ID of server the image is bound to. Only set for images of type `backup`.
-	Created     string                 `json:"created"`                // Point in time when the image was created (in ISO-8601 format)
-	CreatedFrom *CreatedFrom           `json:"created_from"`           // Information about the server the image was created from
-	Deprecated  *string                `json:"deprecated"`             // Point in time when the image is considered to be deprecated (in ISO-8601 format)
-	Description string                 `json:"description"`            // Description of the image
-	DiskSize    float64                `json:"disk_size"`              // Size of the disk contained in the image in GB.
-	ID          float64                `json:"id"`                     // ID of the image
-	ImageSize   *float64               `json:"image_size"`             // Size of the image file in our storage in GB. For snapshot images this is the value; relevant for calculating costs for the image.
-	Labels      map[string]interface{} `json:"labels"`                 // User-defined labels (key-value pairs)
-	Name        *string                `json:"name"`                   // Unique identifier of the image. This value is only set for system images.
-	OSFlavor    OSFlavor               `json:"os_flavor"`              // Flavor of operating system contained in the image
-	OSVersion   *string                `json:"os_version"`             // Operating system version
-	Protection  ImageProtection        `json:"protection"`             // Protection configuration for the image
-	RapidDeploy *bool                  `json:"rapid_deploy,omitempty"` // Indicates that rapid deploy of the image is available
-	Status      ImageStatus            `json:"status"`                 // Whether the image can be used or if it's still being created
-	Type        ImageType              `json:"type"`                   // Type of the image
+	Architecture Architecture           `json:"architecture"`           // CPU architecture the image is built for
+	BoundTo      *int64                 `json:"bound_to"`               // ID of server the image is bound to. Only set for images of type `backup`.
+	Created      string                 `json:"created"`                // Point in time when the image was created (in ISO-8601 format)
+	CreatedFrom  *CreatedFrom           `json:"created_from"`           // Information about the server the image was created from
+	Deleted      *string                `json:"deleted"`                // Point in time where the image was deleted (in ISO-8601 format)
+	Deprecated   *string                `json:"deprecated"`             // Point in time when the image is considered to be deprecated (in ISO-8601 format)
+	Description  string                 `json:"description"`            // Description of the image
+	DiskSize     float64                `json:"disk_size"`              // Size of the disk contained in the image in GB.
+	ID           int64                  `json:"id"`                     // ID of the image
+	ImageSize    *float64               `json:"image_size"`             // Size of the image file in our storage in GB. For snapshot images this is the value; relevant for calculating costs for the image.
+	Labels       map[string]interface{} `json:"labels"`                 // User-defined labels (key-value pairs)
+	Name         *string                `json:"name"`                   // Unique identifier of the image. This value is only set for system images.
+	OSFlavor     OSFlavor               `json:"os_flavor"`              // Flavor of operating system contained in the image
+	OSVersion    *string                `json:"os_version"`             // Operating system version
+	Protection   ImageProtection        `json:"protection"`             // Protection configuration for the image
+	RapidDeploy  *bool                  `json:"rapid_deploy,omitempty"` // Indicates that rapid deploy of the image is available
+	Status       ImageStatus            `json:"status"`                 // Whether the image can be used or if it's still being created
+	Type         ImageType              `json:"type"`                   // Type of the image
 }
 
 type CreatedFrom struct {
-	ID   float64 `json:"id"`   // ID of the server the image was created from
-	Name string  `json:"name"` // Server name at the time the image was created
+	ID   int64  `json:"id"`   // ID of the server the image was created from
+	Name string `json:"name"` // Server name at the time the image was created
 }
 
 // Protection configuration for the image
@@ -115,6 +202,14 @@ type ImageProtection struct {
 	Delete bool `json:"delete"` // If true, prevents the snapshot from being deleted
 }
 
+// Private network the server is attached to
+type PrivateNet struct {
+	AliasIPs   []string `json:"alias_ips"`   // Additional IPs of the server in the network
+	IP         string   `json:"ip"`          // IP of the server in the network
+	MACAddress string   `json:"mac_address"` // MAC address of the server's interface in the network
+	Network    int64    `json:"network"`     // ID of the network
+}
+
 // Protection configuration for the server
 type ServerProtection struct {
 	Delete  bool `json:"delete"`  // If true, prevents the server from being deleted
@@ -124,23 +219,57 @@ type ServerProtection struct {
 // Public network information. The servers ipv4 address can be found in
 // `public_net->ipv4->ip`
 type PublicNet struct {
-	FloatingIPS []float64 `json:"floating_ips"` // IDs of floating IPs assigned to this server.
-	Ipv4        Ipv4      `json:"ipv4"`         // IP address (v4) and its reverse dns entry of this server.
-	Ipv6        Ipv6      `json:"ipv6"`         // IPv6 network assigned to this server and its reverse dns entry.
+	IPv4        *PublicIP        `json:"ipv4"`         // IP address (v4) and its reverse dns entry of this server, or null if the server has no IPv4.
+	IPv6        *PublicIPv6      `json:"ipv6"`         // IPv6 network assigned to this server and its reverse dns entry, or null if the server has no IPv6.
+	FloatingIPs []int64          `json:"floating_ips"` // IDs of floating IPs assigned to this server.
+	Firewalls   []FirewallStatus `json:"firewalls"`    // Firewalls applied to the public network interface of this server.
+}
+
+// IPv4Address returns the IPv4 address of the server, or an empty string if it has none.
+func (p *PublicNet) IPv4Address() string {
+	if p.IPv4 == nil {
+		return ""
+	}
+	return p.IPv4.IP
+}
+
+// IPv6Network returns the IPv6 network of the server, or an empty string if it has none.
+func (p *PublicNet) IPv6Network() string {
+	if p.IPv6 == nil {
+		return ""
+	}
+	return p.IPv6.IP
+}
+
+// FirewallIDs returns the IDs of the firewalls applied to the public network interface.
+func (p *PublicNet) FirewallIDs() []int64 {
+	ids := make([]int64, 0, len(p.Firewalls))
+	for _, f := range p.Firewalls {
+		ids = append(ids, f.ID)
+	}
+	return ids
 }
 
 // IP address (v4) and its reverse dns entry of this server.
-type Ipv4 struct {
+type PublicIP struct {
+	ID      int64  `json:"id"`      // ID of the primary IP backing this address
 	Blocked bool   `json:"blocked"` // If the IP is blocked by our anti abuse dept
 	DNSPtr  string `json:"dns_ptr"` // Reverse DNS PTR entry for the IPv4 addresses of this server.
 	IP      string `json:"ip"`      // IP address (v4) of this server.
 }
 
 // IPv6 network assigned to this server and its reverse dns entry.
-type Ipv6 struct {
+type PublicIPv6 struct {
+	ID      int64    `json:"id"`      // ID of the primary IP backing this network
 	Blocked bool     `json:"blocked"` // If the IP is blocked by our anti abuse dept
 	DNSPtr  []DNSPtr `json:"dns_ptr"` // Reverse DNS PTR entries for the IPv6 addresses of this server, `null` by default.
-	IP      string   `json:"ip"`      // IP address (v4) of this server.
+	IP      string   `json:"ip"`      // IPv6 network of this server.
+}
+
+// Firewall applied to a network interface of a server
+type FirewallStatus struct {
+	ID     int64  `json:"id"`     // ID of the firewall
+	Status string `json:"status"` // Whether the firewall is applied or still pending
 }
 
 type DNSPtr struct {
@@ -150,33 +279,62 @@ type DNSPtr struct {
 
 // Type of server - determines how much ram, disk and cpu a server has
 type ServerType struct {
-	Cores       float64     `json:"cores"`        // Number of cpu cores a server of this type will have
-	CPUType     CPUType     `json:"cpu_type"`     // Type of cpu.
-	Description string      `json:"description"`  // Description of the server type
-	Disk        float64     `json:"disk"`         // Disk size a server of this type will have in GB
-	ID          float64     `json:"id"`           // ID of the server type
-	Memory      float64     `json:"memory"`       // Memory a server of this type will have in GB
-	Name        string      `json:"name"`         // Unique identifier of the server type
-	Prices      []Price     `json:"prices"`       // Prices in different Locations
-	StorageType StorageType `json:"storage_type"` // Type of server boot drive. Local has higher speed. Network has better availability.
+	Architecture Architecture `json:"architecture"` // CPU architecture of servers of this type
+	Cores        float64      `json:"cores"`        // Number of cpu cores a server of this type will have
+	CPUType      CPUType      `json:"cpu_type"`     // Type of cpu.
+	Deprecated   bool         `json:"deprecated"`   // True if the server type is deprecated
+	Description  string       `json:"description"`  // Description of the server type
+	Disk         float64      `json:"disk"`         // Disk size a server of this type will have in GB
+	ID           int64        `json:"id"`           // ID of the server type
+	Memory       float64      `json:"memory"`       // Memory a server of this type will have in GB
+	Name         string       `json:"name"`         // Unique identifier of the server type
+	Prices       []Price      `json:"prices"`       // Prices in different Locations
+	StorageType  StorageType  `json:"storage_type"` // Type of server boot drive. Local has higher speed. Network has better availability.
+	Deprecation  *Deprecation `json:"deprecation"`  // Deprecation details of the server type, or null if it is not deprecated
+}
+
+// IncludedTrafficInLocation returns the free traffic per billing period in
+// bytes for servers of this type in the given location, or 0 if the type
+// has no price for that location.
+func (t *ServerType) IncludedTrafficInLocation(loc string) uint64 {
+	for _, p := range t.Prices {
+		if p.Location == loc {
+			return p.IncludedTraffic
+		}
+	}
+	return 0
+}
+
+// Deprecation details of a resource that is being retired
+type Deprecation struct {
+	Announced        time.Time `json:"announced"`         // Point in time when the deprecation was announced
+	UnavailableAfter time.Time `json:"unavailable_after"` // Point in time after which the resource can no longer be used for new servers
 }
 
 type Price struct {
-	Location     string       `json:"location"`      // Name of the location the price is for
-	PriceHourly  PriceHourly  `json:"price_hourly"`  // Hourly costs for a server type in this location
-	PriceMonthly PriceMonthly `json:"price_monthly"` // Monthly costs for a server type in this location
+	IncludedTraffic   uint64            `json:"included_traffic"`     // Free traffic per billing period in bytes in this location
+	Location          string            `json:"location"`             // Name of the location the price is for
+	PriceHourly       PriceHourly       `json:"price_hourly"`         // Hourly costs for a server type in this location
+	PriceMonthly      PriceMonthly      `json:"price_monthly"`        // Monthly costs for a server type in this location
+	PricePerTBTraffic PricePerTBTraffic `json:"price_per_tb_traffic"` // Costs per TB of traffic above the included traffic in this location
+}
+
+// Costs per TB of traffic above the included traffic in this location
+type PricePerTBTraffic struct {
+	Gross Amount `json:"gross"` // Price with VAT added
+	Net   Amount `json:"net"`   // Price without VAT
 }
 
 // Hourly costs for a server type in this location
 type PriceHourly struct {
-	Gross string `json:"gross"` // Price with VAT added
-	Net   string `json:"net"`   // Price without VAT
+	Gross Amount `json:"gross"` // Price with VAT added
+	Net   Amount `json:"net"`   // Price without VAT
 }
 
 // Monthly costs for a server type in this location
 type PriceMonthly struct {
-	Gross string `json:"gross"` // Price with VAT added
-	Net   string `json:"net"`   // Price without VAT
+	Gross Amount `json:"gross"` // Price with VAT added
+	Net   Amount `json:"net"`   // Price without VAT
 }
 
 // Type of the ISO
@@ -216,6 +374,14 @@ const (
 	System   ImageType = "system"
 )
 
+// CPU architecture
+type Architecture string
+
+const (
+	ArchitectureARM Architecture = "arm"
+	ArchitectureX86 Architecture = "x86"
+)
+
 // Type of cpu.
 type CPUType string
 
@@ -0,0 +1,79 @@
+// To parse and unparse this JSON data, add this code to your project and do:
+//
+//    bytes, err = serverChangeTypeRequest.Marshal()
+//    rebuildResult, err := UnmarshalRebuildResult(bytes)
+//    rescueResult, err := UnmarshalRescueResult(bytes)
+//    imageCreateResult, err := UnmarshalImageCreateResult(bytes)
+
+package models
+
+import "encoding/json"
+
+func (r *ServerChangeTypeRequest) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type ServerChangeTypeRequest struct {
+	ServerType  string `json:"server_type"`  // ID or name of the server type the server should migrate to
+	UpgradeDisk bool   `json:"upgrade_disk"` // If false, do not upgrade the disk. This allows downgrading the server type later.
+}
+
+func (r *ServerRebuildRequest) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type ServerRebuildRequest struct {
+	Image string `json:"image"` // ID or name of the image to rebuild the server from
+}
+
+func UnmarshalRebuildResult(data []byte) (RebuildResult, error) {
+	var r RebuildResult
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+type RebuildResult struct {
+	Action       ActionClass `json:"action"`
+	RootPassword *string     `json:"root_password"` // New root password if the image has no SSH keys configured, otherwise null
+}
+
+func (r *ServerEnableRescueRequest) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type ServerEnableRescueRequest struct {
+	Type    *string `json:"type,omitempty"`     // Type of rescue system to boot (defaults to linux64)
+	SSHKeys []int   `json:"ssh_keys,omitempty"` // IDs of SSH keys to inject into the rescue system
+}
+
+func UnmarshalRescueResult(data []byte) (RescueResult, error) {
+	var r RescueResult
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+type RescueResult struct {
+	Action       ActionClass `json:"action"`
+	RootPassword *string     `json:"root_password"` // Password that will be set for this server once the action succeeds
+}
+
+func (r *ServerCreateImageRequest) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type ServerCreateImageRequest struct {
+	Type        ImageType              `json:"type,omitempty"`        // Type of image to create (defaults to snapshot)
+	Description *string                `json:"description,omitempty"` // Description of the image
+	Labels      map[string]interface{} `json:"labels,omitempty"`      // User-defined labels (key-value pairs)
+}
+
+func UnmarshalImageCreateResult(data []byte) (ImageCreateResult, error) {
+	var r ImageCreateResult
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+type ImageCreateResult struct {
+	Action ActionClass `json:"action"`
+	Image  Image       `json:"image"`
+}
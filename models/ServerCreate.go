@@ -18,7 +18,7 @@ type ServerCreateRequest struct {
 	ServerType       string                 `json:"server_type"`                  // ID or name of the server type this server should be created with
 	SSHKeys          []string               `json:"ssh_keys,omitempty"`           // SSH key IDs or names which should be injected into the server at creation time
 	StartAfterCreate *bool                  `json:"start_after_create,omitempty"` // Start Server right after creation. Defaults to true.
-	UserData         *string                `json:"user_data,omitempty"`          // Cloud-Init user data to use during server creation. This field is limited to 32KiB.
+	UserData         *string                `json:"user_data,omitempty"`          // Cloud-Init user data to use during server creation. Sent whenever set, for any image type. This field is limited to 32KiB.
 	Volumes          []string               `json:"volumes,omitempty"`            // Volume IDs which should be attached to the server at the creation time. Volumes must be; in the same location.
 	Location         *string                `json:"location,omitempty"`           // ID or name of location to create server in.
 	Datacenter       *string                `json:"datacenter,omitempty"`         // ID or name of datacenter to create server in.
@@ -35,7 +35,8 @@ func (r *ServerCreateResponse) Marshal() ([]byte, error) {
 }
 
 type ServerCreateResponse struct {
-	Action       ActionClass `json:"action"`
-	RootPassword *string     `json:"root_password"` // Root password when no SSH keys have been specified
-	Server       ServerClass `json:"server"`
+	Action       ActionClass   `json:"action"`
+	NextActions  []ActionClass `json:"next_actions"`  // Actions started after the server was created, e.g. attaching volumes
+	RootPassword *string       `json:"root_password"` // Root password when no SSH keys have been specified
+	Server       ServerClass   `json:"server"`
 }
@@ -22,13 +22,8 @@ type ServerMetrics struct {
 }
 
 type Metrics struct {
-	End        string  `json:"end"`         // End of period of metrics reported (in ISO-8601 format)
-	Start      string  `json:"start"`       // Start of period of metrics reported (in ISO-8601 format)
-	Step       float64 `json:"step"`        // Resolution of results in seconds.
-	TimeSeries Series  `json:"time_series"` // Hash with timeseries information, containing the name of timeseries as key
-}
-
-// Hash with timeseries information, containing the name of timeseries as key
-type Series struct {
-	NameOfTimeseries interface{} `json:"name_of_timeseries"`
+	End        string                `json:"end"`         // End of period of metrics reported (in ISO-8601 format)
+	Start      string                `json:"start"`       // Start of period of metrics reported (in ISO-8601 format)
+	Step       float64               `json:"step"`        // Resolution of results in seconds.
+	TimeSeries map[string]TimeSeries `json:"time_series"` // Hash with timeseries information, containing the name of timeseries as key
 }
@@ -0,0 +1,24 @@
+// To parse and unparse this JSON data, add this code to your project and do:
+//
+//    serverTypes, err := UnmarshalServerTypes(bytes)
+//    bytes, err = serverTypes.Marshal()
+
+package models
+
+import "encoding/json"
+
+func UnmarshalServerTypes(data []byte) (ServerTypesList, error) {
+	var r ServerTypesList
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *ServerTypesList) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// ServerTypesList is named to avoid clashing with ServerTypes, the server
+// types a datacenter can handle.
+type ServerTypesList struct {
+	ServerTypes []ServerType `json:"server_types"`
+}
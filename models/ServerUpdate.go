@@ -11,8 +11,8 @@ func (r *ServerUpdateRequest) Marshal() ([]byte, error) {
 }
 
 type ServerUpdateRequest struct {
-	Labels map[string]interface{} `json:"labels,omitempty"` // New labels
-	Name   *string                `json:"name,omitempty"`   // New name to set
+	Labels *map[string]interface{} `json:"labels,omitempty"` // New labels, replacing all existing ones. Nil leaves them unchanged, an empty map removes all.
+	Name   *string                 `json:"name,omitempty"`   // New name to set
 }
 
 func UnmarshalServerUpdateResponse(data []byte) (ServerUpdateResponse, error) {
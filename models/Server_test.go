@@ -0,0 +1,113 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestServerTrafficNullAndZero(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		isNil   bool
+		want    uint64
+	}{
+		{
+			name:    "null",
+			fixture: `{"id":1,"outgoing_traffic":null,"ingoing_traffic":null,"included_traffic":null}`,
+			isNil:   true,
+		},
+		{
+			name:    "missing",
+			fixture: `{"id":1}`,
+			isNil:   true,
+		},
+		{
+			name:    "zero",
+			fixture: `{"id":1,"outgoing_traffic":0,"ingoing_traffic":0,"included_traffic":0}`,
+		},
+		{
+			name:    "set",
+			fixture: `{"id":1,"outgoing_traffic":123456,"ingoing_traffic":123456,"included_traffic":123456}`,
+			want:    123456,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s ServerClass
+			if err := json.Unmarshal([]byte(tt.fixture), &s); err != nil {
+				t.Fatal(err)
+			}
+			for field, ptr := range map[string]*uint64{
+				"outgoing_traffic": s.OutgoingTraffic,
+				"ingoing_traffic":  s.IngoingTraffic,
+				"included_traffic": s.IncludedTraffic,
+			} {
+				if (ptr == nil) != tt.isNil {
+					t.Errorf("%s: expected nil: %t, got %v", field, tt.isNil, ptr)
+				}
+			}
+			if s.OutgoingTrafficBytes() != tt.want || s.IngoingTrafficBytes() != tt.want || s.IncludedTrafficBytes() != tt.want {
+				t.Errorf("expected %d bytes of traffic, got %d, %d and %d",
+					tt.want, s.OutgoingTrafficBytes(), s.IngoingTrafficBytes(), s.IncludedTrafficBytes())
+			}
+		})
+	}
+}
+
+const publicNetFixture = `{
+	"ipv4": {
+		"id": 42,
+		"blocked": false,
+		"dns_ptr": "server01.example.com",
+		"ip": "1.2.3.4"
+	},
+	"ipv6": {
+		"id": 43,
+		"blocked": true,
+		"dns_ptr": [{"dns_ptr": "server.example.com", "ip": "2001:db8::1"}],
+		"ip": "2001:db8::/64"
+	},
+	"floating_ips": [478, 9007199254740993],
+	"firewalls": [{"id": 38, "status": "applied"}, {"id": 39, "status": "pending"}]
+}`
+
+func TestPublicNet(t *testing.T) {
+	var p PublicNet
+	dec := json.NewDecoder(strings.NewReader(publicNetFixture))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p); err != nil {
+		t.Fatal(err)
+	}
+
+	if p.IPv4Address() != "1.2.3.4" || p.IPv4.ID != 42 || p.IPv4.Blocked || p.IPv4.DNSPtr != "server01.example.com" {
+		t.Errorf("unexpected IPv4 %+v", p.IPv4)
+	}
+	if p.IPv6Network() != "2001:db8::/64" || p.IPv6.ID != 43 || !p.IPv6.Blocked {
+		t.Errorf("unexpected IPv6 %+v", p.IPv6)
+	}
+	if want := []DNSPtr{{DNSPtr: "server.example.com", IP: "2001:db8::1"}}; !reflect.DeepEqual(p.IPv6.DNSPtr, want) {
+		t.Errorf("expected IPv6 DNS pointers %v, got %v", want, p.IPv6.DNSPtr)
+	}
+	if want := []int64{478, 9007199254740993}; !reflect.DeepEqual(p.FloatingIPs, want) {
+		t.Errorf("expected floating IPs %v, got %v", want, p.FloatingIPs)
+	}
+	if want := []int64{38, 39}; !reflect.DeepEqual(p.FirewallIDs(), want) {
+		t.Errorf("expected firewalls %v, got %v", want, p.FirewallIDs())
+	}
+	if p.Firewalls[1].Status != "pending" {
+		t.Errorf("expected firewall 39 to be pending, got %q", p.Firewalls[1].Status)
+	}
+}
+
+func TestPublicNetWithoutAddresses(t *testing.T) {
+	var p PublicNet
+	if err := json.Unmarshal([]byte(`{"ipv4":null,"ipv6":null,"floating_ips":[],"firewalls":[]}`), &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.IPv4Address() != "" || p.IPv6Network() != "" || len(p.FirewallIDs()) != 0 {
+		t.Errorf("expected no addresses or firewalls, got %+v", p)
+	}
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// TimeSeries holds the values of a single metrics time series.
+type TimeSeries struct {
+	Values []TimeSeriesValue `json:"values"`
+}
+
+// TimeSeriesValue is a single [timestamp, value] pair of a time series.
+// The API encodes the value as a string to avoid loss of precision.
+type TimeSeriesValue struct {
+	Timestamp float64 // Point in time of the value (unix timestamp)
+	Value     string  // Value at that point in time
+}
+
+// UnmarshalJSON decodes a [timestamp, "value"] pair.
+func (v *TimeSeriesValue) UnmarshalJSON(data []byte) error {
+	var pair []json.RawMessage
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return err
+	}
+	if len(pair) != 2 {
+		return fmt.Errorf("time series value must have 2 elements, got %d", len(pair))
+	}
+	if err := json.Unmarshal(pair[0], &v.Timestamp); err != nil {
+		return err
+	}
+	return json.Unmarshal(pair[1], &v.Value)
+}
+
+// MarshalJSON encodes the value as a [timestamp, "value"] pair.
+func (v TimeSeriesValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{v.Timestamp, v.Value})
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTimeSeriesValue(t *testing.T) {
+	var v TimeSeriesValue
+	if err := json.Unmarshal([]byte(`[1435781470.622, "42.5"]`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Timestamp != 1435781470.622 || v.Value != "42.5" {
+		t.Errorf("unexpected value %+v", v)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `[1435781470.622,"42.5"]` {
+		t.Errorf("expected the pair to be marshalled back, got %s", b)
+	}
+
+	for _, data := range []string{`[1435781470.622]`, `[1435781470.622, 42.5]`, `{"timestamp": 1}`} {
+		if err := json.Unmarshal([]byte(data), &v); err == nil {
+			t.Errorf("expected an error for %s", data)
+		}
+	}
+}
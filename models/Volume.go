@@ -0,0 +1,116 @@
+// To parse and unparse this JSON data, add this code to your project and do:
+//
+//    volumes, err := UnmarshalVolumes(bytes)
+//    volume, err := UnmarshalVolume(bytes)
+//    volumeCreateResponse, err := UnmarshalVolumeCreateResponse(bytes)
+//    bytes, err = volumeCreateRequest.Marshal()
+
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+func UnmarshalVolumes(data []byte) (Volumes, error) {
+	var r Volumes
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *Volumes) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type Volumes struct {
+	Volumes []VolumeClass `json:"volumes"`
+}
+
+func UnmarshalVolume(data []byte) (Volume, error) {
+	var r Volume
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+type Volume struct {
+	Volume VolumeClass `json:"volume"`
+}
+
+type VolumeClass struct {
+	Created     time.Time              `json:"created"`      // Point in time when the volume was created
+	Format      *string                `json:"format"`       // Filesystem of the volume if formatted on creation, null if not formatted
+	ID          int64                  `json:"id"`           // ID of the volume
+	Labels      map[string]interface{} `json:"labels"`       // User-defined labels (key-value pairs)
+	LinuxDevice string                 `json:"linux_device"` // Device path on the file system for the volume
+	Location    Location               `json:"location"`     // Location of the volume. Volume can only be attached to servers in the same location.
+	Name        string                 `json:"name"`         // Name of the volume
+	Protection  VolumeProtection       `json:"protection"`   // Protection configuration for the volume
+	Server      *int64                 `json:"server"`       // ID of the server the volume is attached to, null if not attached at all
+	Size        float64                `json:"size"`         // Size in GB of the volume
+	Status      VolumeStatus           `json:"status"`       // Current status of the volume
+}
+
+// Protection configuration for the volume
+type VolumeProtection struct {
+	Delete bool `json:"delete"` // If true, prevents the volume from being deleted
+}
+
+// Current status of the volume
+type VolumeStatus string
+
+const (
+	VolumeAvailable VolumeStatus = "available"
+	VolumeCreating  VolumeStatus = "creating"
+)
+
+func (r *VolumeCreateRequest) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type VolumeCreateRequest struct {
+	Automount *bool                  `json:"automount,omitempty"` // Auto-mount volume after attach. Server must be provided.
+	Format    *string                `json:"format,omitempty"`    // Format volume after creation. One of: xfs, ext4
+	Labels    map[string]interface{} `json:"labels,omitempty"`    // User-defined labels (key-value pairs)
+	Location  *string                `json:"location,omitempty"`  // Location to create the volume in (can be omitted if server is specified)
+	Name      string                 `json:"name"`                // Name of the volume
+	Server    *int                   `json:"server,omitempty"`    // Server to which to attach the volume once it's created (volume will be created in the same location as the server)
+	Size      int                    `json:"size"`                // Size of the volume in GB
+}
+
+func UnmarshalVolumeCreateResponse(data []byte) (VolumeCreateResponse, error) {
+	var r VolumeCreateResponse
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+type VolumeCreateResponse struct {
+	Action      ActionClass   `json:"action"`
+	NextActions []ActionClass `json:"next_actions"`
+	Volume      VolumeClass   `json:"volume"`
+}
+
+func (r *VolumeUpdateRequest) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type VolumeUpdateRequest struct {
+	Labels *map[string]interface{} `json:"labels,omitempty"` // New labels, replacing all existing ones
+	Name   *string                 `json:"name,omitempty"`   // New volume name
+}
+
+func (r *VolumeAttachRequest) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type VolumeAttachRequest struct {
+	Automount *bool `json:"automount,omitempty"` // Auto-mount the volume after attaching it
+	Server    int   `json:"server"`              // ID of the server the volume will be attached to
+}
+
+func (r *VolumeResizeRequest) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type VolumeResizeRequest struct {
+	Size int `json:"size"` // New volume size in GB (must be greater than current size)
+}
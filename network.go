@@ -0,0 +1,29 @@
+package gohetz
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const networksUrl = "/networks/"
+
+// NetworkExists reports whether the network with the given ID exists.
+// Errors other than the API reporting the network as not found are returned.
+// Networks are not modelled by the library yet, so the response body is
+// not decoded.
+func (c *Client) NetworkExists(ctx context.Context, id int) (bool, error) {
+	req, err := c.NewRequest(ctx, http.MethodGet, fmt.Sprintf("%s%d", networksUrl, id), nil)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = c.Do(req, nil)
+	if IsError(err, ErrorCodeNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,69 @@
+package gohetz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"./models"
+)
+
+// ProtectionOpts specifies the protection to set on a resource. Nil fields
+// are left unchanged.
+type ProtectionOpts struct {
+	Delete  *bool // Prevent the resource from being deleted
+	Rebuild *bool // Prevent the server from being rebuilt (servers only)
+}
+
+// ChangeProtection changes the protection of the resource of the given type and ID.
+func (c *Client) ChangeProtection(ctx context.Context, resource ResourceType, id int, opts ProtectionOpts) (*models.Action, *Response, error) {
+	path, err := resource.path()
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.Rebuild != nil && resource != ResourceTypeServer {
+		return nil, nil, errors.New("hcloud: rebuild protection is only supported on servers")
+	}
+
+	request := &models.ChangeProtectionRequest{
+		Delete:  opts.Delete,
+		Rebuild: opts.Rebuild,
+	}
+	req, err := c.NewJSONRequest(ctx, http.MethodPost, fmt.Sprintf("%s%d/actions/change_protection", path, id), request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw, err := c.Do(req, nil)
+	if err != nil {
+		return nil, raw, err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(raw.Body)
+	if err != nil {
+		return nil, raw, err
+	}
+
+	var action models.Action
+	if err := c.decode(bodyBytes, &action); err != nil {
+		return nil, raw, err
+	}
+
+	return &action, raw, nil
+}
+
+// ChangeServerProtectionAndWait sets the delete and rebuild protection of the
+// server with the given ID and waits for the change to be applied, so that a
+// following delete or rebuild does not race it.
+func (c *Client) ChangeServerProtectionAndWait(ctx context.Context, id int, delete, rebuild bool) error {
+	action, _, err := c.ChangeProtection(ctx, ResourceTypeServer, id, ProtectionOpts{
+		Delete:  &delete,
+		Rebuild: &rebuild,
+	})
+	if err != nil {
+		return err
+	}
+	return c.WaitForAction(ctx, action)
+}
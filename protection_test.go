@@ -0,0 +1,72 @@
+package gohetz
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestChangeServerProtectionAndWait(t *testing.T) {
+	rr := newRequestRecorder(t)
+	rr.respond("POST /servers/1/actions/change_protection", http.StatusCreated, `{"action":{"id":7,"status":"running"}}`)
+	rr.respond("GET /actions/7", http.StatusOK, `{"action":{"id":7,"status":"success","progress":100}}`)
+	client := newRecorderClient(t, rr)
+
+	if err := client.ChangeServerProtectionAndWait(context.Background(), 1, true, false); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"POST /servers/1/actions/change_protection", "GET /actions/7"}; !reflect.DeepEqual(rr.recorded(), want) {
+		t.Errorf("expected requests %v, got %v", want, rr.recorded())
+	}
+	body := rr.body("POST /servers/1/actions/change_protection")
+	if body["delete"] != true || body["rebuild"] != false {
+		t.Errorf("unexpected body %v", body)
+	}
+}
+
+func TestChangeProtectionDispatch(t *testing.T) {
+	tests := []struct {
+		resource ResourceType
+		path     string
+	}{
+		{ResourceTypeServer, "/servers/3/actions/change_protection"},
+		{ResourceTypeVolume, "/volumes/3/actions/change_protection"},
+		{ResourceTypeFloatingIP, "/floating_ips/3/actions/change_protection"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.resource), func(t *testing.T) {
+			rr := newRequestRecorder(t)
+			rr.respond("POST "+tt.path, http.StatusCreated, `{"action":{"id":7,"command":"change_protection","status":"running"}}`)
+			client := newRecorderClient(t, rr)
+
+			protect := true
+			action, _, err := client.ChangeProtection(context.Background(), tt.resource, 3, ProtectionOpts{Delete: &protect})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if action.Server.ID != 7 {
+				t.Errorf("expected action 7, got %d", action.Server.ID)
+			}
+			if body := rr.body("POST " + tt.path); !reflect.DeepEqual(body, map[string]interface{}{"delete": true}) {
+				t.Errorf("expected only delete protection in the body, got %v", body)
+			}
+		})
+	}
+}
+
+func TestChangeProtectionRejectsRebuildOnNonServers(t *testing.T) {
+	rr := newRequestRecorder(t)
+	client := newRecorderClient(t, rr)
+
+	protect := true
+	if _, _, err := client.ChangeProtection(context.Background(), ResourceTypeVolume, 3, ProtectionOpts{Rebuild: &protect}); err == nil {
+		t.Error("expected an error for rebuild protection on a volume")
+	}
+	if _, _, err := client.ChangeProtection(context.Background(), ResourceType("bucket"), 3, ProtectionOpts{Delete: &protect}); err == nil {
+		t.Error("expected an error for an unknown resource type")
+	}
+	if len(rr.recorded()) != 0 {
+		t.Errorf("expected no requests, got %v", rr.recorded())
+	}
+}
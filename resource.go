@@ -0,0 +1,71 @@
+package gohetz
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ResourceType is the type of a resource managed through the API.
+type ResourceType string
+
+// Resource types supported by the generic resource helpers.
+const (
+	ResourceTypeServer       ResourceType = "server"
+	ResourceTypeVolume       ResourceType = "volume"
+	ResourceTypeImage        ResourceType = "image"
+	ResourceTypeNetwork      ResourceType = "network"
+	ResourceTypeFloatingIP   ResourceType = "floating_ip"
+	ResourceTypePrimaryIP    ResourceType = "primary_ip"
+	ResourceTypeLoadBalancer ResourceType = "load_balancer"
+)
+
+// path returns the URL path of the resource collection, with a trailing slash.
+func (r ResourceType) path() (string, error) {
+	switch r {
+	case ResourceTypeServer, ResourceTypeVolume, ResourceTypeImage, ResourceTypeNetwork,
+		ResourceTypeFloatingIP, ResourceTypePrimaryIP, ResourceTypeLoadBalancer:
+		return "/" + string(r) + "s/", nil
+	default:
+		return "", fmt.Errorf("hcloud: unknown resource type %q", r)
+	}
+}
+
+// FindByLabel returns all resources of the given type matching the label
+// selector, as the typed slice of that resource, e.g. []models.ServerClass
+// for servers and []models.VolumeClass for volumes. Only servers and
+// volumes are supported so far.
+func (c *Client) FindByLabel(ctx context.Context, resource ResourceType, selector string) (interface{}, error) {
+	if err := validateLabelSelector(selector); err != nil {
+		return nil, err
+	}
+
+	opts := allListOpts(ListOpts{LabelSelector: selector})
+	switch resource {
+	case ResourceTypeServer:
+		return c.allServers(ctx, opts)
+	case ResourceTypeVolume:
+		volumes, err := c.GetAllVolumes(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		return volumes.Volumes, nil
+	default:
+		return nil, fmt.Errorf("hcloud: listing %s resources by label is not supported", resource)
+	}
+}
+
+// validateLabelSelector checks that selector is a non-empty, comma-separated
+// list of non-empty requirements. The requirements themselves are left for
+// the API to validate.
+func validateLabelSelector(selector string) error {
+	if strings.TrimSpace(selector) == "" {
+		return fmt.Errorf("hcloud: label selector is required")
+	}
+	for _, requirement := range strings.Split(selector, ",") {
+		if strings.TrimSpace(requirement) == "" {
+			return fmt.Errorf("hcloud: label selector %q has an empty requirement", selector)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package gohetz
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"./models"
+)
+
+func TestFindByLabel(t *testing.T) {
+	rr := newRequestRecorder(t)
+	for key, body := range map[string]string{
+		"GET /servers/": `{"servers":[{"id":1,"name":"a","labels":{"env":"dev"}}]}`,
+		"GET /volumes/": `{"volumes":[{"id":2,"name":"data","labels":{"env":"dev"}},{"id":3,"name":"logs","labels":{"env":"dev"}}]}`,
+	} {
+		body := body
+		rr.responses[key] = func(w http.ResponseWriter, r *http.Request) {
+			if got := r.URL.Query().Get("label_selector"); got != "env=dev" {
+				t.Errorf("%s: expected label_selector env=dev, got %q", r.URL.Path, got)
+			}
+			writeJSON(w, http.StatusOK, body)
+		}
+	}
+	client := newRecorderClient(t, rr)
+
+	result, err := client.FindByLabel(context.Background(), ResourceTypeServer, "env=dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	servers, ok := result.([]models.ServerClass)
+	if !ok || len(servers) != 1 || servers[0].ID != 1 {
+		t.Errorf("expected server 1 as []models.ServerClass, got %#v", result)
+	}
+
+	result, err = client.FindByLabel(context.Background(), ResourceTypeVolume, "env=dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	volumes, ok := result.([]models.VolumeClass)
+	if !ok || len(volumes) != 2 || volumes[0].ID != 2 || volumes[1].ID != 3 {
+		t.Errorf("expected volumes 2 and 3 as []models.VolumeClass, got %#v", result)
+	}
+}
+
+func TestFindByLabelUnsupported(t *testing.T) {
+	rr := newRequestRecorder(t)
+	client := newRecorderClient(t, rr)
+
+	if _, err := client.FindByLabel(context.Background(), ResourceTypeImage, "env=dev"); err == nil {
+		t.Error("expected an error for an unsupported resource type")
+	}
+	if _, err := client.FindByLabel(context.Background(), ResourceTypeServer, ""); err == nil {
+		t.Error("expected an error for an empty selector")
+	}
+	if len(rr.recorded()) != 0 {
+		t.Errorf("expected no requests, got %v", rr.recorded())
+	}
+}
+
+// existsTests are the responses shared by the tests of the Exists helpers.
+var existsTests = []struct {
+	name    string
+	status  int
+	body    string
+	want    bool
+	wantErr bool
+}{
+	{name: "found", status: http.StatusOK, body: `{"server":{"id":1},"volume":{"id":1}}`, want: true},
+	{name: "not found", status: http.StatusNotFound, body: `{"error":{"code":"not_found","message":"not found"}}`, want: false},
+	{name: "error", status: http.StatusUnauthorized, body: `{"error":{"code":"unauthorized","message":"unable to authenticate"}}`, wantErr: true},
+}
+
+func TestExists(t *testing.T) {
+	helpers := []struct {
+		name   string
+		path   string
+		exists func(*Client, context.Context, int) (bool, error)
+	}{
+		{"ServerExists", "/servers/1", (*Client).ServerExists},
+		{"VolumeExists", "/volumes/1", (*Client).VolumeExists},
+		{"NetworkExists", "/networks/1", (*Client).NetworkExists},
+	}
+	for _, h := range helpers {
+		for _, tt := range existsTests {
+			t.Run(h.name+"/"+tt.name, func(t *testing.T) {
+				client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Path != h.path {
+						t.Errorf("expected %s, got %s", h.path, r.URL.Path)
+					}
+					writeJSON(w, tt.status, tt.body)
+				})
+
+				exists, err := h.exists(client, context.Background(), 1)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("unexpected error %v", err)
+				}
+				if exists != tt.want {
+					t.Errorf("expected %t, got %t", tt.want, exists)
+				}
+			})
+		}
+	}
+}
@@ -2,42 +2,202 @@ package gohetz
 
 import (
 	"./models"
-	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
 )
 
 const serversUrl = "/servers/"
 
-func (c *Client) GetAllServers() (*models.Servers, error) {
+// maxUserDataSize is the maximum size of cloud-init user data accepted by the API.
+const maxUserDataSize = 32 * 1024
 
-	req, err := c.NewRequest(context.Background(), http.MethodGet, fmt.Sprintf(serversUrl), nil)
+// validateUserData checks that user data fits the API's size limit and is
+// valid UTF-8, as it is sent as a JSON string.
+func validateUserData(userData string) error {
+	if len(userData) > maxUserDataSize {
+		return fmt.Errorf("hcloud: user data is %d bytes, exceeding the limit of %d bytes", len(userData), maxUserDataSize)
+	}
+	if !utf8.ValidString(userData) {
+		return errors.New("hcloud: user data is not valid UTF-8")
+	}
+	return nil
+}
+
+// ErrUnknownUserDataFormat is returned by UserDataFromFile along with the
+// user data if it starts with neither "#cloud-config" nor "#!". Cloud-init
+// may not pick up such data; callers may ignore the error otherwise.
+var ErrUnknownUserDataFormat = errors.New("hcloud: user data starts with neither #cloud-config nor #!")
+
+// UserDataFromFile reads cloud-init user data for ServerCreateOpts.UserData
+// from the file at path, checking the API's size limit.
+func UserDataFromFile(path string) (string, error) {
+	b, err := ioutil.ReadFile(path)
 	if err != nil {
-		return nil, err
+		return "", err
+	}
+	userData := string(b)
+	if err := validateUserData(userData); err != nil {
+		return "", err
 	}
+	if !strings.HasPrefix(userData, "#cloud-config") && !strings.HasPrefix(userData, "#!") {
+		return userData, ErrUnknownUserDataFormat
+	}
+	return userData, nil
+}
 
-	raw, err := c.Do(req, nil)
+// GetAllServers returns the servers matching opts, following pagination
+// until the last page. opts.Page is ignored.
+func (c *Client) GetAllServers(ctx context.Context, opts ListOpts) (*models.Servers, error) {
+	servers, err := c.allServers(ctx, allListOpts(opts))
 	if err != nil {
 		return nil, err
 	}
+	return &models.Servers{Servers: servers}, nil
+}
 
-	bodyBytes, err := ioutil.ReadAll(raw.Body)
+// GetAllServersLegacy returns the servers without a context or list options.
+//
+// Deprecated: Use GetAllServers instead.
+func (c *Client) GetAllServersLegacy() (*models.Servers, error) {
+	return c.GetAllServers(context.Background(), ListOpts{})
+}
+
+// GetServerByName returns the server with the given name, or nil if there
+// is none. The API matches names exactly.
+func (c *Client) GetServerByName(ctx context.Context, name string) (*models.Server, error) {
+	vals := url.Values{}
+	vals.Add("name", name)
+	servers, _, err := c.listServers(ctx, vals)
 	if err != nil {
 		return nil, err
 	}
+	if len(servers.Servers) == 0 {
+		return nil, nil
+	}
+	return &models.Server{Server: servers.Servers[0]}, nil
+}
+
+// GetServersNewestFirst returns a single page of servers matching opts,
+// sorted by creation date with the newest server first.
+func (c *Client) GetServersNewestFirst(ctx context.Context, opts ListOpts) (*models.Servers, error) {
+	vals := valuesForListOpts(opts)
+	vals.Set("sort", "created:desc")
+	servers, _, err := c.listServers(ctx, vals)
+	return servers, err
+}
+
+// GetServersByIDs returns the servers with the given IDs, keyed by ID. It
+// lists all servers rather than fetching each one, so large batches cost a
+// request per page. IDs without a server report a not_found Error; if
+// listing fails, every ID reports the listing error.
+func (c *Client) GetServersByIDs(ctx context.Context, ids []int) (map[int]*models.ServerClass, map[int]error) {
+	servers := make(map[int]*models.ServerClass)
+	errs := make(map[int]error)
+
+	all, err := c.allServers(ctx, allListOpts(ListOpts{}))
+	if err != nil {
+		for _, id := range ids {
+			errs[id] = err
+		}
+		return servers, errs
+	}
 
-	servers, err := models.UnmarshalServers(bodyBytes)
+	byID := make(map[int]*models.ServerClass, len(all))
+	for i := range all {
+		byID[int(all[i].ID)] = &all[i]
+	}
+	for _, id := range ids {
+		if server, ok := byID[id]; ok {
+			servers[id] = server
+			continue
+		}
+		errs[id] = Error{
+			Code:    ErrorCodeNotFound,
+			Message: fmt.Sprintf("server %d not found", id),
+		}
+	}
+	return servers, errs
+}
+
+// allServers returns the servers of all pages matching opts.
+func (c *Client) allServers(ctx context.Context, opts ListOpts) ([]models.ServerClass, error) {
+	var servers []models.ServerClass
+	_, err := c.all(func(page int) (*Response, error) {
+		opts.Page = page
+		p, resp, err := c.listServers(ctx, valuesForListOpts(opts))
+		if err != nil {
+			return resp, err
+		}
+		servers = append(servers, p.Servers...)
+		return resp, nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	return servers, nil
+}
+
+func (c *Client) listServers(ctx context.Context, vals url.Values) (*models.Servers, *Response, error) {
+	req, err := c.NewRequest(ctx, http.MethodGet, serversUrl+"?"+vals.Encode(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	return &servers, nil
+	raw, err := c.Do(req, nil)
+	if err != nil {
+		return nil, raw, err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(raw.Body)
+	if err != nil {
+		return nil, raw, err
+	}
+
+	var servers models.Servers
+	if err := c.decode(bodyBytes, &servers); err != nil {
+		return nil, raw, err
+	}
+
+	return &servers, raw, nil
+}
+
+func (c *Client) GetServerBy(ID int64) (*models.Server, error) {
+	return c.getServer(context.Background(), int(ID))
+}
+
+// GetServer returns the server with the given ID. If the server does not
+// exist, nil is returned for both the server and the error.
+func (c *Client) GetServer(ctx context.Context, id int) (*models.Server, error) {
+	server, err := c.getServer(ctx, id)
+	if IsError(err, ErrorCodeNotFound) {
+		return nil, nil
+	}
+	return server, err
 }
 
-func (c *Client) GetServerBy(ID float64) (*models.Server, error) {
-	req, err := c.NewRequest(context.Background(), http.MethodGet, fmt.Sprintf("%s%.f", serversUrl, ID), nil)
+// ServerExists reports whether the server with the given ID exists.
+// Errors other than the API reporting the server as not found are returned.
+func (c *Client) ServerExists(ctx context.Context, id int) (bool, error) {
+	_, err := c.getServer(ctx, id)
+	if IsError(err, ErrorCodeNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *Client) getServer(ctx context.Context, id int) (*models.Server, error) {
+	req, err := c.NewRequest(ctx, http.MethodGet, fmt.Sprintf("%s%d", serversUrl, id), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -52,20 +212,256 @@ func (c *Client) GetServerBy(ID float64) (*models.Server, error) {
 		return nil, err
 	}
 
-	servers, err := models.UnmarshalServer(bodyBytes)
-	if err != nil {
+	var servers models.Server
+	if err := c.decode(bodyBytes, &servers); err != nil {
 		return nil, err
 	}
 
 	return &servers, nil
 }
 
-func (c *Client) CreateServerWith(request *models.ServerCreateRequest) (*models.ServerCreateResponse, error) {
-	s, err := request.Marshal()
+// ServerCreateOpts specifies options for creating a server.
+type ServerCreateOpts struct {
+	Name             string            // Name of the server (required)
+	ServerType       string            // ID or name of the server type (required)
+	Image            string            // ID or name of the image (required)
+	Location         string            // ID or name of the location to create the server in
+	Datacenter       string            // ID or name of the datacenter to create the server in
+	SSHKeys          []int             // IDs of SSH keys to inject into the server
+	UserData         string            // Cloud-Init user data
+	Labels           map[string]string // User-defined labels
+	StartAfterCreate *bool             // Start the server right after creation (defaults to true)
+
+	// EphemeralSSHKey is a public key in OpenSSH format to inject into the
+	// server in addition to SSHKeys. The SSH key with its fingerprint is
+	// used if there is one; otherwise it is created.
+	EphemeralSSHKey string
+
+	// Protection, if set, is applied once the server has been created. The
+	// API cannot protect a server during creation, so the server is
+	// unprotected until its create action has finished and the protection
+	// change has been applied; a failure in between leaves it unprotected.
+	Protection *ProtectionOpts
+}
+
+func (o ServerCreateOpts) validate() error {
+	switch {
+	case o.Name == "":
+		return errors.New("hcloud: server name is required")
+	case o.ServerType == "":
+		return errors.New("hcloud: server type is required")
+	case o.Image == "":
+		return errors.New("hcloud: image is required")
+	}
+	return nil
+}
+
+func (o ServerCreateOpts) request() *models.ServerCreateRequest {
+	request := &models.ServerCreateRequest{
+		Name:             o.Name,
+		ServerType:       o.ServerType,
+		Image:            o.Image,
+		StartAfterCreate: o.StartAfterCreate,
+	}
+	if o.Location != "" {
+		request.Location = &o.Location
+	}
+	if o.Datacenter != "" {
+		request.Datacenter = &o.Datacenter
+	}
+	for _, id := range o.SSHKeys {
+		request.SSHKeys = append(request.SSHKeys, strconv.Itoa(id))
+	}
+	if o.UserData != "" {
+		request.UserData = &o.UserData
+	}
+	if o.Labels != nil {
+		request.Labels = make(map[string]interface{}, len(o.Labels))
+		for k, v := range o.Labels {
+			request.Labels[k] = v
+		}
+	}
+	return request
+}
+
+// CreateServer creates a server. It returns the created server and the
+// action creating it, which can be passed to WaitForAction. If
+// opts.Protection is set, CreateServer waits for the action and then applies
+// the protection; if that fails, the created server is returned with the
+// error.
+func (c *Client) CreateServer(ctx context.Context, opts ServerCreateOpts) (*models.Server, *models.Action, error) {
+	if err := opts.validate(); err != nil {
+		return nil, nil, err
+	}
+	if err := c.resolveEphemeralSSHKey(ctx, &opts); err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := c.createServer(ctx, opts.request())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	server, action := &models.Server{Server: resp.Server}, &models.Action{Server: resp.Action}
+	if err := c.protectCreatedServer(ctx, server, action, opts.Protection); err != nil {
+		return server, action, err
+	}
+	return server, action, nil
+}
+
+// protectCreatedServer waits for action creating server and then applies
+// protection, if set, updating server to match.
+func (c *Client) protectCreatedServer(ctx context.Context, server *models.Server, action *models.Action, protection *ProtectionOpts) error {
+	if protection == nil {
+		return nil
+	}
+	if err := c.WaitForAction(ctx, action); err != nil {
+		return err
+	}
+	protect, _, err := c.ChangeProtection(ctx, ResourceTypeServer, int(server.Server.ID), *protection)
+	if err != nil {
+		return err
+	}
+	if err := c.WaitForAction(ctx, protect); err != nil {
+		return err
+	}
+	if protection.Delete != nil {
+		server.Server.Protection.Delete = *protection.Delete
+	}
+	if protection.Rebuild != nil {
+		server.Server.Protection.Rebuild = *protection.Rebuild
+	}
+	return nil
+}
+
+// resolveEphemeralSSHKey finds or creates the SSH key of
+// opts.EphemeralSSHKey, if set, and moves it to opts.SSHKeys.
+func (c *Client) resolveEphemeralSSHKey(ctx context.Context, opts *ServerCreateOpts) error {
+	if opts.EphemeralSSHKey == "" {
+		return nil
+	}
+	fingerprint, err := SSHKeyFingerprint(opts.EphemeralSSHKey)
+	if err != nil {
+		return err
+	}
+	name := "ephemeral-" + strings.Replace(fingerprint, ":", "", -1)
+	sshKey, err := c.EnsureSSHKey(ctx, name, opts.EphemeralSSHKey)
+	if err != nil {
+		return err
+	}
+	opts.SSHKeys = append(append([]int(nil), opts.SSHKeys...), int(sshKey.SSHKey.ID))
+	opts.EphemeralSSHKey = ""
+	return nil
+}
+
+func (c *Client) CreateServerWith(request *models.ServerCreateRequest) (*models.ServerCreateResponse, error) {
+	return c.createServer(context.Background(), request)
+}
+
+// CreateServers creates one server per name from the shared template base,
+// with at most concurrency creations in flight at a time. Results and errors
+// are keyed by server name; names not attempted because ctx was done report
+// ctx.Err(). Each server's options are validated like for CreateServer; an
+// EphemeralSSHKey is resolved once for all servers. A server that was
+// created but could not be protected is reported in both maps.
+func (c *Client) CreateServers(ctx context.Context, base ServerCreateOpts, names []string, concurrency int) (map[string]*models.Server, map[string]error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, concurrency)
+		servers = make(map[string]*models.Server)
+		errs    = make(map[string]error)
+	)
+	if err := c.resolveEphemeralSSHKey(ctx, &base); err != nil {
+		for _, name := range names {
+			errs[name] = err
+		}
+		return servers, errs
+	}
+	for _, name := range names {
+		opts := base
+		opts.Name = name
+		if err := opts.validate(); err != nil {
+			mu.Lock()
+			errs[name] = err
+			mu.Unlock()
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			mu.Lock()
+			errs[name] = ctx.Err()
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func(name string, request *models.ServerCreateRequest) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			resp, err := c.createServer(ctx, request)
+			if err != nil {
+				mu.Lock()
+				errs[name] = err
+				mu.Unlock()
+				return
+			}
+			server := &models.Server{Server: resp.Server}
+			err = c.protectCreatedServer(ctx, server, &models.Action{Server: resp.Action}, base.Protection)
+			mu.Lock()
+			defer mu.Unlock()
+			servers[name] = server
+			if err != nil {
+				errs[name] = err
+			}
+		}(name, opts.request())
+	}
+	wg.Wait()
+
+	return servers, errs
+}
+
+// DiffServers compares a desired set of servers with the actual ones, keyed
+// by name. It returns the desired servers missing from actual and the actual
+// servers not in desired, both in their original order. Servers present in
+// both are left alone; their other attributes are not compared.
+func DiffServers(desired []ServerCreateOpts, actual []*models.Server) (toCreate []ServerCreateOpts, toDelete []*models.Server) {
+	wanted := make(map[string]bool, len(desired))
+	for _, opts := range desired {
+		wanted[opts.Name] = true
 	}
-	req, err := c.NewRequest(context.Background(), http.MethodPost, fmt.Sprintf(serversUrl), bytes.NewReader(s))
+	existing := make(map[string]bool, len(actual))
+	for _, server := range actual {
+		if server == nil {
+			continue
+		}
+		existing[server.Server.Name] = true
+		if !wanted[server.Server.Name] {
+			toDelete = append(toDelete, server)
+		}
+	}
+	for _, opts := range desired {
+		if !existing[opts.Name] {
+			toCreate = append(toCreate, opts)
+		}
+	}
+	return toCreate, toDelete
+}
+
+func (c *Client) createServer(ctx context.Context, request *models.ServerCreateRequest) (*models.ServerCreateResponse, error) {
+	if request.UserData != nil {
+		if err := validateUserData(*request.UserData); err != nil {
+			return nil, err
+		}
+	}
+	req, err := c.NewJSONRequest(ctx, http.MethodPost, serversUrl, request)
 	if err != nil {
 		return nil, err
 	}
@@ -80,20 +476,57 @@ func (c *Client) CreateServerWith(request *models.ServerCreateRequest) (*models.
 		return nil, err
 	}
 
-	server, err := models.UnmarshalCreateServerResponse(bodyBytes)
-	if err != nil {
+	var server models.ServerCreateResponse
+	if err := c.decode(bodyBytes, &server); err != nil {
 		return nil, err
 	}
 
 	return &server, nil
 }
 
-func (c *Client) UpdateServerBy(ID float64, request *models.ServerUpdateRequest) (*models.ServerUpdateResponse, error) {
-	s, err := request.Marshal()
+func (c *Client) UpdateServerBy(ID int64, request *models.ServerUpdateRequest) (*models.ServerUpdateResponse, error) {
+	return c.updateServer(context.Background(), int(ID), request)
+}
+
+// AddServerLabels adds labels to the server with the given ID, keeping its
+// existing labels. Labels with the same key are overwritten.
+func (c *Client) AddServerLabels(ctx context.Context, id int, labels map[string]string) (*models.ServerUpdateResponse, error) {
+	return c.modifyServerLabels(ctx, id, func(current map[string]interface{}) {
+		for k, v := range labels {
+			current[k] = v
+		}
+	})
+}
+
+// RemoveServerLabels removes the labels with the given keys from the server
+// with the given ID, keeping all other labels.
+func (c *Client) RemoveServerLabels(ctx context.Context, id int, keys []string) (*models.ServerUpdateResponse, error) {
+	return c.modifyServerLabels(ctx, id, func(current map[string]interface{}) {
+		for _, k := range keys {
+			delete(current, k)
+		}
+	})
+}
+
+// modifyServerLabels fetches the labels of a server, applies f to them and
+// stores the result. Changes made between the fetch and the update are lost.
+func (c *Client) modifyServerLabels(ctx context.Context, id int, f func(map[string]interface{})) (*models.ServerUpdateResponse, error) {
+	server, err := c.getServer(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	req, err := c.NewRequest(context.Background(), http.MethodPut, fmt.Sprintf("%s%.f", serversUrl, ID), bytes.NewReader(s))
+
+	labels := make(map[string]interface{}, len(server.Server.Labels))
+	for k, v := range server.Server.Labels {
+		labels[k] = v
+	}
+	f(labels)
+
+	return c.updateServer(ctx, id, &models.ServerUpdateRequest{Labels: &labels})
+}
+
+func (c *Client) updateServer(ctx context.Context, id int, request *models.ServerUpdateRequest) (*models.ServerUpdateResponse, error) {
+	req, err := c.NewJSONRequest(ctx, http.MethodPut, fmt.Sprintf("%s%d", serversUrl, id), request)
 	if err != nil {
 		return nil, err
 	}
@@ -108,16 +541,58 @@ func (c *Client) UpdateServerBy(ID float64, request *models.ServerUpdateRequest)
 		return nil, err
 	}
 
-	server, err := models.UnmarshalServerUpdateResponse(bodyBytes)
-	if err != nil {
+	var server models.ServerUpdateResponse
+	if err := c.decode(bodyBytes, &server); err != nil {
 		return nil, err
 	}
 
 	return &server, nil
 }
 
-func (c *Client) DeleteServerBy(ID float64) (*models.ServerDeleteResponse, error) {
-	req, err := c.NewRequest(context.Background(), http.MethodDelete, fmt.Sprintf("%s%.f", serversUrl, ID), nil)
+func (c *Client) DeleteServerBy(ID int64) (*models.ServerDeleteResponse, error) {
+	return c.deleteServer(context.Background(), int(ID))
+}
+
+// DeleteServer deletes the server with the given ID and returns the action
+// deleting it. If the server does not exist, an Error with code not_found is
+// returned, which can be checked with IsError.
+func (c *Client) DeleteServer(ctx context.Context, id int) (*models.Action, error) {
+	resp, err := c.deleteServer(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Action{Server: resp.Action}, nil
+}
+
+// ServerDeleteOpts specifies options for deleting a server.
+type ServerDeleteOpts struct {
+	// Force removes the server's delete protection before deleting it.
+	// Protection exists to prevent accidental data loss, so only set this
+	// when the server is really meant to go.
+	Force bool
+}
+
+// DeleteServerWithOpts deletes the server with the given ID like
+// DeleteServer. Without opts.Force, deleting a protected server fails with
+// the API's error. With it, the delete protection is removed first and the
+// deletion only starts once that change has finished.
+func (c *Client) DeleteServerWithOpts(ctx context.Context, id int, opts ServerDeleteOpts) (*models.Action, error) {
+	if opts.Force {
+		server, err := c.getServer(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if server.Server.Protection.Delete {
+			if err := c.ChangeServerProtectionAndWait(ctx, id, false, server.Server.Protection.Rebuild); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return c.DeleteServer(ctx, id)
+}
+
+func (c *Client) deleteServer(ctx context.Context, id int) (*models.ServerDeleteResponse, error) {
+	req, err := c.NewRequest(ctx, http.MethodDelete, fmt.Sprintf("%s%d", serversUrl, id), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -132,10 +607,24 @@ func (c *Client) DeleteServerBy(ID float64) (*models.ServerDeleteResponse, error
 		return nil, err
 	}
 
-	response, err := models.UnmarshalServerDeleteResponse(bodyBytes)
-	if err != nil {
+	var response models.ServerDeleteResponse
+	if err := c.decode(bodyBytes, &response); err != nil {
 		return nil, err
 	}
 
 	return &response, nil
 }
+
+// MoveServerToProject always returns ErrNotSupported without calling the API,
+// as servers cannot be moved between projects. Create a snapshot and
+// recreate the server from it in the target project instead.
+func (c *Client) MoveServerToProject(ctx context.Context, id int, project string) error {
+	return notSupported("MoveServerToProject", "servers cannot be moved between projects; create a snapshot and recreate the server in the target project")
+}
+
+// MigrateServer always returns ErrNotSupported without calling the API, as
+// there is no endpoint to trigger a live migration. Hetzner migrates servers
+// on its own during host maintenance.
+func (c *Client) MigrateServer(ctx context.Context, id int) error {
+	return notSupported("MigrateServer", "the API has no endpoint for live-migrating servers")
+}
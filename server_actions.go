@@ -0,0 +1,318 @@
+package gohetz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"./models"
+)
+
+// PoweronServer starts the server with the given ID.
+func (c *Client) PoweronServer(ctx context.Context, id int) (*models.Action, error) {
+	return c.doServerAction(ctx, id, "poweron", nil)
+}
+
+// PoweroffServer cuts power to the server with the given ID. This forcefully
+// stops it without giving the operating system a chance to shut down.
+func (c *Client) PoweroffServer(ctx context.Context, id int) (*models.Action, error) {
+	return c.doServerAction(ctx, id, "poweroff", nil)
+}
+
+// PoweronServersBySelector starts all servers matching the label selector.
+// See PoweroffServersBySelector for the results.
+func (c *Client) PoweronServersBySelector(ctx context.Context, selector string) (map[int]*models.Action, map[int]error, error) {
+	return c.serverActionBySelector(ctx, selector, c.PoweronServer)
+}
+
+// PoweroffServersBySelector cuts power to all servers matching the label
+// selector. Actions and errors are keyed by server ID; a server failing does
+// not stop the others. The error is set if the selector is invalid or the
+// servers cannot be listed, in which case no action is issued.
+func (c *Client) PoweroffServersBySelector(ctx context.Context, selector string) (map[int]*models.Action, map[int]error, error) {
+	return c.serverActionBySelector(ctx, selector, c.PoweroffServer)
+}
+
+// serverActionBySelector issues do for each server matching selector.
+func (c *Client) serverActionBySelector(ctx context.Context, selector string, do func(context.Context, int) (*models.Action, error)) (map[int]*models.Action, map[int]error, error) {
+	if err := validateLabelSelector(selector); err != nil {
+		return nil, nil, err
+	}
+	servers, err := c.allServers(ctx, allListOpts(ListOpts{LabelSelector: selector}))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	actions := make(map[int]*models.Action, len(servers))
+	errs := make(map[int]error)
+	for _, server := range servers {
+		id := int(server.ID)
+		action, err := do(ctx, id)
+		if err != nil {
+			errs[id] = err
+			continue
+		}
+		actions[id] = action
+	}
+	return actions, errs, nil
+}
+
+// RebootServer reboots the server with the given ID gracefully.
+func (c *Client) RebootServer(ctx context.Context, id int) (*models.Action, error) {
+	return c.doServerAction(ctx, id, "reboot", nil)
+}
+
+// ResetServer cuts power to the server with the given ID and starts it again.
+func (c *Client) ResetServer(ctx context.Context, id int) (*models.Action, error) {
+	return c.doServerAction(ctx, id, "reset", nil)
+}
+
+// ShutdownServer shuts down the server with the given ID gracefully.
+func (c *Client) ShutdownServer(ctx context.Context, id int) (*models.Action, error) {
+	return c.doServerAction(ctx, id, "shutdown", nil)
+}
+
+// doServerAction performs an action returning only the action envelope.
+func (c *Client) doServerAction(ctx context.Context, id int, action string, body interface{}) (*models.Action, error) {
+	bodyBytes, err := c.serverAction(ctx, id, action, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var a models.Action
+	if err := c.decode(bodyBytes, &a); err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+// serverAction posts body to the given action of a server and returns the
+// raw response body.
+func (c *Client) serverAction(ctx context.Context, id int, action string, body interface{}) ([]byte, error) {
+	req, err := c.NewJSONRequest(ctx, http.MethodPost, fmt.Sprintf("%s%d/actions/%s", serversUrl, id, action), body)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := c.Do(req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return ioutil.ReadAll(raw.Body)
+}
+
+// ServerChangeTypeOpts specifies options for changing the type of a server.
+type ServerChangeTypeOpts struct {
+	ServerType  string // ID or name of the new server type
+	UpgradeDisk bool   // Resize the disk to the size of the new server type
+}
+
+// ChangeServerType changes the type of the server with the given ID. The
+// server must be powered off; otherwise the API's error is returned as is.
+// Upgrading the disk cannot be undone, so UpgradeDisk must be false to be
+// able to downsize the server again later.
+func (c *Client) ChangeServerType(ctx context.Context, id int, opts ServerChangeTypeOpts) (*models.Action, error) {
+	return c.doServerAction(ctx, id, "change_type", &models.ServerChangeTypeRequest{
+		ServerType:  opts.ServerType,
+		UpgradeDisk: opts.UpgradeDisk,
+	})
+}
+
+// ChangeServerTypeReversible changes the type of the server with the given
+// ID without upgrading its disk, so the change can be undone later. The disk
+// keeps its current size, even if the new type offers a larger one.
+func (c *Client) ChangeServerTypeReversible(ctx context.Context, id int, serverType string) (*models.Action, error) {
+	return c.ChangeServerType(ctx, id, ServerChangeTypeOpts{ServerType: serverType, UpgradeDisk: false})
+}
+
+// RebuildServer rebuilds the server with the given ID from an image, given
+// by ID or name. All data on the server's disk is lost. The result holds a
+// new root password if the image has no SSH keys configured.
+func (c *Client) RebuildServer(ctx context.Context, id int, image string) (*models.RebuildResult, error) {
+	return c.RebuildServerWithOpts(ctx, id, ServerRebuildOpts{Image: image})
+}
+
+// ServerRebuildOpts specifies options for rebuilding a server.
+type ServerRebuildOpts struct {
+	Image string // ID or name of the image to rebuild from (required)
+
+	// CheckProtection fetches the server first and fails with a
+	// protected Error without rebuilding if its rebuild protection is
+	// enabled. Otherwise the API rejects the rebuild with the same code.
+	CheckProtection bool
+}
+
+// RebuildServerWithOpts rebuilds the server with the given ID like
+// RebuildServer, with additional options.
+func (c *Client) RebuildServerWithOpts(ctx context.Context, id int, opts ServerRebuildOpts) (*models.RebuildResult, error) {
+	if opts.Image == "" {
+		return nil, errors.New("hcloud: image is required")
+	}
+	if opts.CheckProtection {
+		server, err := c.getServer(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if server.Server.Protection.Rebuild {
+			return nil, Error{
+				Code:    ErrorCodeProtected,
+				Message: fmt.Sprintf("server %d has rebuild protection enabled", id),
+			}
+		}
+	}
+
+	bodyBytes, err := c.serverAction(ctx, id, "rebuild", &models.ServerRebuildRequest{Image: opts.Image})
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.RebuildResult
+	if err := c.decode(bodyBytes, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// RebuildServerAndWait rebuilds the server with the given ID like
+// RebuildServer and waits for the rebuild to finish. It returns the new root
+// password, or an empty string if the image has SSH keys configured.
+func (c *Client) RebuildServerAndWait(ctx context.Context, id int, image string) (string, error) {
+	result, err := c.RebuildServer(ctx, id, image)
+	if err != nil {
+		return "", err
+	}
+	if err := c.WaitForAction(ctx, &models.Action{Server: result.Action}); err != nil {
+		return "", err
+	}
+	if result.RootPassword == nil {
+		return "", nil
+	}
+	return *result.RootPassword, nil
+}
+
+// RescueOpts specifies options for enabling the rescue system of a server.
+type RescueOpts struct {
+	Type    string // Type of rescue system, e.g. "linux64" (empty means default)
+	SSHKeys []int  // IDs of SSH keys to inject into the rescue system
+
+	// AllSSHKeys injects all SSH keys of the account if SSHKeys is
+	// empty, so the rescue system is reachable by key.
+	AllSSHKeys bool
+}
+
+// EnableRescue enables the rescue system of the server with the given ID,
+// which it boots into on its next reboot. The root password of the rescue
+// system is only returned by this call.
+func (c *Client) EnableRescue(ctx context.Context, id int, opts RescueOpts) (*models.RescueResult, error) {
+	request := &models.ServerEnableRescueRequest{SSHKeys: opts.SSHKeys}
+	if opts.AllSSHKeys && len(opts.SSHKeys) == 0 {
+		sshKeys, err := c.GetAllSSHKeys(ctx, ListOpts{})
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range sshKeys.SSHKeys {
+			request.SSHKeys = append(request.SSHKeys, int(key.ID))
+		}
+	}
+	if opts.Type != "" {
+		request.Type = &opts.Type
+	}
+
+	bodyBytes, err := c.serverAction(ctx, id, "enable_rescue", request)
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.RescueResult
+	if err := c.decode(bodyBytes, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// DisableRescue disables the rescue system of the server with the given ID.
+func (c *Client) DisableRescue(ctx context.Context, id int) (*models.Action, error) {
+	return c.doServerAction(ctx, id, "disable_rescue", nil)
+}
+
+// ImageCreateOpts specifies options for creating an image from a server.
+type ImageCreateOpts struct {
+	Type        models.ImageType  // Snapshot or Backup (empty means snapshot)
+	Description string            // Description of the image
+	Labels      map[string]string // User-defined labels
+}
+
+func (o ImageCreateOpts) validate() error {
+	switch o.Type {
+	case "", models.Snapshot, models.Backup:
+		return nil
+	default:
+		return fmt.Errorf("hcloud: invalid image type %q, must be %s or %s", o.Type, models.Snapshot, models.Backup)
+	}
+}
+
+// CreateImage creates an image from the disk of the server with the given
+// ID. It returns the new image along with the action creating it.
+func (c *Client) CreateImage(ctx context.Context, serverID int, opts ImageCreateOpts) (*models.ImageCreateResult, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	request := &models.ServerCreateImageRequest{Type: opts.Type}
+	if opts.Description != "" {
+		request.Description = &opts.Description
+	}
+	if opts.Labels != nil {
+		request.Labels = make(map[string]interface{}, len(opts.Labels))
+		for k, v := range opts.Labels {
+			request.Labels[k] = v
+		}
+	}
+
+	bodyBytes, err := c.serverAction(ctx, serverID, "create_image", request)
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.ImageCreateResult
+	if err := c.decode(bodyBytes, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// SnapshotServer creates a snapshot of the disk of the server with the given
+// ID and waits for both the action creating it to finish and the image to
+// become available. It returns the available image.
+func (c *Client) SnapshotServer(ctx context.Context, id int, description string) (*models.Image, error) {
+	result, err := c.CreateImage(ctx, id, ImageCreateOpts{
+		Type:        models.Snapshot,
+		Description: description,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := c.WaitForAction(ctx, &models.Action{Server: result.Action}); err != nil {
+		return nil, err
+	}
+
+	image := result.Image
+	for image.Status != models.Available {
+		if err := sleepContext(ctx, c.pollInterval); err != nil {
+			return nil, err
+		}
+		current, err := c.getImage(ctx, int(image.ID))
+		if err != nil {
+			return nil, err
+		}
+		image = current.Image
+	}
+	return &image, nil
+}
@@ -0,0 +1,185 @@
+package gohetz
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestRebuildServerAndWait(t *testing.T) {
+	rr := newRequestRecorder(t)
+	rr.respond("POST /servers/1/actions/rebuild", http.StatusCreated, `{"action":{"id":5,"status":"running"},"root_password":"secret"}`)
+	rr.respond("GET /actions/5", http.StatusOK, `{"action":{"id":5,"status":"success","progress":100}}`)
+	client := newRecorderClient(t, rr)
+
+	password, err := client.RebuildServerAndWait(context.Background(), 1, "ubuntu-22.04")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if password != "secret" {
+		t.Errorf("expected root password %q, got %q", "secret", password)
+	}
+	if want := []string{"POST /servers/1/actions/rebuild", "GET /actions/5"}; !reflect.DeepEqual(rr.recorded(), want) {
+		t.Errorf("expected requests %v, got %v", want, rr.recorded())
+	}
+	if image := rr.body("POST /servers/1/actions/rebuild")["image"]; image != "ubuntu-22.04" {
+		t.Errorf("expected image ubuntu-22.04 in body, got %v", image)
+	}
+}
+
+func TestSnapshotServerWaitsForActionAndImage(t *testing.T) {
+	rr := newRequestRecorder(t)
+	rr.respond("POST /servers/1/actions/create_image", http.StatusCreated, `{"action":{"id":5,"status":"running"},"image":{"id":9,"status":"creating","type":"snapshot"}}`)
+	rr.respond("GET /actions/5", http.StatusOK, `{"action":{"id":5,"status":"success","progress":100}}`)
+	var imagePolls int
+	rr.responses["GET /images/9"] = func(w http.ResponseWriter, r *http.Request) {
+		imagePolls++
+		status := "creating"
+		if imagePolls > 1 {
+			status = "available"
+		}
+		writeJSON(w, http.StatusOK, `{"image":{"id":9,"status":"`+status+`","type":"snapshot","description":"before upgrade"}}`)
+	}
+	client := newRecorderClient(t, rr)
+
+	image, err := client.SnapshotServer(context.Background(), 1, "before upgrade")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if image.ID != 9 || image.Status != "available" {
+		t.Errorf("unexpected image %+v", image)
+	}
+	want := []string{"POST /servers/1/actions/create_image", "GET /actions/5", "GET /images/9", "GET /images/9"}
+	if !reflect.DeepEqual(rr.recorded(), want) {
+		t.Errorf("expected requests %v, got %v", want, rr.recorded())
+	}
+	if body := rr.body("POST /servers/1/actions/create_image"); body["type"] != "snapshot" || body["description"] != "before upgrade" {
+		t.Errorf("unexpected body %v", body)
+	}
+}
+
+func TestRebuildServerChecksProtection(t *testing.T) {
+	rr := newRequestRecorder(t)
+	rr.respond("GET /servers/1", http.StatusOK, `{"server":{"id":1,"protection":{"delete":false,"rebuild":true}}}`)
+	client := newRecorderClient(t, rr)
+
+	_, err := client.RebuildServerWithOpts(context.Background(), 1, ServerRebuildOpts{Image: "ubuntu-22.04", CheckProtection: true})
+	if !IsError(err, ErrorCodeProtected) {
+		t.Errorf("expected protected error, got %v", err)
+	}
+	if want := []string{"GET /servers/1"}; !reflect.DeepEqual(rr.recorded(), want) {
+		t.Errorf("expected only %v, got %v", want, rr.recorded())
+	}
+}
+
+func TestChangeServerType(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/servers/1/actions/change_type" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		if body["server_type"] != "cx32" || body["upgrade_disk"] != true {
+			t.Errorf("expected server_type cx32 with upgrade_disk, got %v", body)
+		}
+		writeJSON(w, http.StatusCreated, `{"action":{"id":5,"command":"change_server_type","status":"running","resources":[{"id":1,"type":"server"}]}}`)
+	})
+
+	action, err := client.ChangeServerType(context.Background(), 1, ServerChangeTypeOpts{ServerType: "cx32", UpgradeDisk: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if action.Server.ID != 5 || action.Server.Command != "change_server_type" {
+		t.Errorf("expected change_server_type action 5, got %+v", action.Server)
+	}
+}
+
+func TestChangeServerTypeRunningServer(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusConflict, `{"error":{"code":"server_not_stopped","message":"server must be stopped before changing its type"}}`)
+	})
+
+	_, err := client.ChangeServerType(context.Background(), 1, ServerChangeTypeOpts{ServerType: "cx32"})
+	if !IsError(err, "server_not_stopped") {
+		t.Errorf("expected the API's server_not_stopped error, got %v", err)
+	}
+}
+
+func TestChangeServerTypeReversible(t *testing.T) {
+	rr := newRequestRecorder(t)
+	rr.respond("POST /servers/1/actions/change_type", http.StatusCreated, `{"action":{"id":5,"command":"change_server_type","status":"running"}}`)
+	client := newRecorderClient(t, rr)
+
+	action, err := client.ChangeServerTypeReversible(context.Background(), 1, "cx32")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if action.Server.ID != 5 {
+		t.Errorf("expected action 5, got %d", action.Server.ID)
+	}
+	body := rr.body("POST /servers/1/actions/change_type")
+	if body["upgrade_disk"] != false || body["server_type"] != "cx32" {
+		t.Errorf("expected upgrade_disk false and server_type cx32, got %v", body)
+	}
+}
+
+func TestEnableRescueWithAllSSHKeys(t *testing.T) {
+	rr := newRequestRecorder(t)
+	rr.respond("GET /ssh_keys/", http.StatusOK, `{"ssh_keys":[{"id":3,"name":"a"},{"id":4,"name":"b"}]}`)
+	rr.respond("POST /servers/1/actions/enable_rescue", http.StatusCreated, `{"action":{"id":5,"status":"running"},"root_password":"secret"}`)
+	client := newRecorderClient(t, rr)
+
+	if _, err := client.EnableRescue(context.Background(), 1, RescueOpts{AllSSHKeys: true}); err != nil {
+		t.Fatal(err)
+	}
+	keys := rr.body("POST /servers/1/actions/enable_rescue")["ssh_keys"]
+	if want := []interface{}{float64(3), float64(4)}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("expected ssh_keys %v, got %v", want, keys)
+	}
+}
+
+func TestPoweroffServersBySelector(t *testing.T) {
+	rr := newRequestRecorder(t)
+	rr.responses["GET /servers/"] = func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("label_selector"); got != "env=dev" {
+			t.Errorf("expected label_selector env=dev, got %q", got)
+		}
+		writeJSON(w, http.StatusOK, `{"servers":[{"id":1,"labels":{"env":"dev"}},{"id":2,"labels":{"env":"dev"}}]}`)
+	}
+	rr.respond("POST /servers/1/actions/poweroff", http.StatusCreated, `{"action":{"id":11,"command":"stop_server","status":"running"}}`)
+	rr.respond("POST /servers/2/actions/poweroff", http.StatusCreated, `{"action":{"id":12,"command":"stop_server","status":"running"}}`)
+	client := newRecorderClient(t, rr)
+
+	actions, errs, err := client.PoweroffServersBySelector(context.Background(), "env=dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 0 {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+	if len(actions) != 2 || actions[1].Server.ID != 11 || actions[2].Server.ID != 12 {
+		t.Errorf("expected actions 11 and 12 for servers 1 and 2, got %v", actions)
+	}
+	want := []string{"GET /servers/", "POST /servers/1/actions/poweroff", "POST /servers/2/actions/poweroff"}
+	if !reflect.DeepEqual(rr.recorded(), want) {
+		t.Errorf("expected requests %v, got %v", want, rr.recorded())
+	}
+}
+
+func TestPoweronServersBySelectorInvalidSelector(t *testing.T) {
+	rr := newRequestRecorder(t)
+	client := newRecorderClient(t, rr)
+
+	for _, selector := range []string{"", " ", "env=dev,"} {
+		if _, _, err := client.PoweronServersBySelector(context.Background(), selector); err == nil {
+			t.Errorf("expected an error for selector %q", selector)
+		}
+	}
+	if len(rr.recorded()) != 0 {
+		t.Errorf("expected no requests, got %v", rr.recorded())
+	}
+}
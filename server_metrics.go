@@ -0,0 +1,43 @@
+package gohetz
+
+import (
+	"context"
+	"time"
+
+	"./models"
+)
+
+// ServerMetricType is the type of a server metric.
+type ServerMetricType string
+
+// Available server metric types.
+const (
+	ServerMetricCPU     ServerMetricType = "cpu"
+	ServerMetricDisk    ServerMetricType = "disk"
+	ServerMetricNetwork ServerMetricType = "network"
+)
+
+// ServerMetricsOpts specifies options for fetching server metrics.
+type ServerMetricsOpts struct {
+	Types []ServerMetricType // Metrics to fetch
+	Start time.Time          // Start of the period
+	End   time.Time          // End of the period
+	Step  int                // Resolution of the results in seconds (0 means default)
+}
+
+func (o ServerMetricsOpts) query() metricsQuery {
+	q := metricsQuery{start: o.Start, end: o.End, step: o.Step}
+	for _, t := range o.Types {
+		q.types = append(q.types, string(t))
+	}
+	return q
+}
+
+// GetServerMetrics returns the metrics of the server with the given ID.
+func (c *Client) GetServerMetrics(ctx context.Context, id int, opts ServerMetricsOpts) (*models.ServerMetrics, error) {
+	var metrics models.ServerMetrics
+	if err := c.getMetrics(ctx, serversUrl, id, opts.query(), &metrics); err != nil {
+		return nil, err
+	}
+	return &metrics, nil
+}
@@ -0,0 +1,540 @@
+package gohetz
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"./models"
+)
+
+func TestCreateServers(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Name string `json:"name"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		if req.Name == "bad" {
+			writeJSON(w, http.StatusUnprocessableEntity, `{"error":{"code":"invalid_input","message":"invalid name","details":{"fields":[{"name":"name","messages":["invalid"]}]}}}`)
+			return
+		}
+		writeJSON(w, http.StatusCreated, `{"server":{"id":1,"name":"`+req.Name+`"},"action":{"id":2}}`)
+	})
+
+	base := ServerCreateOpts{ServerType: "cx11", Image: "ubuntu-22.04"}
+	servers, errs := client.CreateServers(context.Background(), base, []string{"a", "bad", "b", ""}, 2)
+
+	if len(servers) != 2 || servers["a"].Server.Name != "a" || servers["b"].Server.Name != "b" {
+		t.Errorf("unexpected servers: %v", servers)
+	}
+	if len(errs) != 2 || !IsError(errs["bad"], ErrorCodeInvalidInput) || errs[""] == nil {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
+
+func TestGetAllServersPaginates(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		queries []url.Values
+	)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.Query())
+		mu.Unlock()
+
+		switch r.URL.Query().Get("page") {
+		case "1":
+			writeJSON(w, http.StatusOK, `{"servers":[{"id":1},{"id":2}],"meta":{"pagination":{"page":1,"per_page":2,"next_page":2,"last_page":2}}}`)
+		case "2":
+			writeJSON(w, http.StatusOK, `{"servers":[{"id":3}],"meta":{"pagination":{"page":2,"per_page":2,"next_page":0,"last_page":2}}}`)
+		default:
+			t.Errorf("unexpected page %q", r.URL.Query().Get("page"))
+			writeJSON(w, http.StatusOK, `{"servers":[]}`)
+		}
+	})
+
+	servers, err := client.GetAllServers(context.Background(), ListOpts{PerPage: 2, LabelSelector: "env=prod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []int64
+	for _, server := range servers.Servers {
+		ids = append(ids, server.ID)
+	}
+	if len(ids) != 3 || ids[0] != 1 || ids[1] != 2 || ids[2] != 3 {
+		t.Errorf("expected servers 1, 2 and 3, got %v", ids)
+	}
+	if len(queries) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(queries))
+	}
+	if q := queries[1]; q.Get("per_page") != "2" || q.Get("label_selector") != "env=prod" {
+		t.Errorf("expected per_page and label_selector on second request, got %v", q)
+	}
+}
+
+func TestGetAllServersRequestsLargestPageByDefault(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("per_page"); got != "50" {
+			t.Errorf("expected per_page=50, got %q", got)
+		}
+		writeJSON(w, http.StatusOK, `{"servers":[{"id":1}]}`)
+	})
+
+	servers, err := client.GetAllServers(context.Background(), ListOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(servers.Servers) != 1 {
+		t.Errorf("expected 1 server, got %d", len(servers.Servers))
+	}
+}
+
+func TestStrictDecodeAcceptsServerResponses(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			writeJSON(w, http.StatusCreated, `{"server":`+serverFixture+`,"action":`+actionFixture+`,"next_actions":[`+actionFixture+`],"root_password":"YItygq1v3GYjjMomLaKc"}`)
+		default:
+			writeJSON(w, http.StatusOK, `{"server":`+serverFixture+`}`)
+		}
+	}, WithStrictDecode())
+
+	if _, err := client.GetServer(context.Background(), 42); err != nil {
+		t.Errorf("GetServer: %v", err)
+	}
+	if _, _, err := client.CreateServer(context.Background(), ServerCreateOpts{Name: "a", ServerType: "cx22", Image: "ubuntu-20.04"}); err != nil {
+		t.Errorf("CreateServer: %v", err)
+	}
+}
+
+func TestServerPrimaryDiskSize(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, `{"server":`+serverFixture+`}`)
+	})
+
+	server, err := client.GetServer(context.Background(), 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := server.Server.PrimaryDiskSizeGB(); got != 50 {
+		t.Errorf("expected a primary disk of 50 GB, got %d", got)
+	}
+}
+
+func TestCreateServerWithEphemeralSSHKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		want     []string
+	}{
+		{
+			name:     "find",
+			existing: `{"ssh_keys":[{"id":3,"fingerprint":"` + testFingerprint + `"}]}`,
+			want:     []string{"GET /ssh_keys/", "POST /servers/"},
+		},
+		{
+			name:     "create",
+			existing: `{"ssh_keys":[]}`,
+			want:     []string{"GET /ssh_keys/", "POST /ssh_keys/", "POST /servers/"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := newRequestRecorder(t)
+			existing := tt.existing
+			rr.responses["GET /ssh_keys/"] = func(w http.ResponseWriter, r *http.Request) {
+				if got := r.URL.Query().Get("fingerprint"); got != testFingerprint {
+					t.Errorf("expected lookup by fingerprint %s, got %q", testFingerprint, got)
+				}
+				writeJSON(w, http.StatusOK, existing)
+			}
+			rr.respond("POST /ssh_keys/", http.StatusCreated, `{"ssh_key":{"id":3,"fingerprint":"`+testFingerprint+`"}}`)
+			rr.respond("POST /servers/", http.StatusCreated, `{"server":{"id":1,"name":"a"},"action":{"id":2}}`)
+			client := newRecorderClient(t, rr)
+
+			_, _, err := client.CreateServer(context.Background(), ServerCreateOpts{
+				Name:            "a",
+				ServerType:      "cx22",
+				Image:           "ubuntu-22.04",
+				SSHKeys:         []int{1},
+				EphemeralSSHKey: testPublicKey,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(rr.recorded(), tt.want) {
+				t.Errorf("expected requests %v, got %v", tt.want, rr.recorded())
+			}
+			if keys := rr.body("POST /servers/")["ssh_keys"]; !reflect.DeepEqual(keys, []interface{}{"1", "3"}) {
+				t.Errorf("expected ssh_keys [1 3], got %v", keys)
+			}
+		})
+	}
+}
+
+func TestCreateServerWithProtection(t *testing.T) {
+	rr := newRequestRecorder(t)
+	rr.respond("POST /servers/", http.StatusCreated, `{"server":{"id":1,"name":"a"},"action":{"id":2,"status":"running"}}`)
+	rr.respond("GET /actions/2", http.StatusOK, `{"action":{"id":2,"status":"success","progress":100}}`)
+	rr.respond("POST /servers/1/actions/change_protection", http.StatusCreated, `{"action":{"id":3,"status":"running"}}`)
+	rr.respond("GET /actions/3", http.StatusOK, `{"action":{"id":3,"status":"success","progress":100}}`)
+	client := newRecorderClient(t, rr)
+
+	protect := true
+	server, _, err := client.CreateServer(context.Background(), ServerCreateOpts{
+		Name:       "a",
+		ServerType: "cx22",
+		Image:      "ubuntu-22.04",
+		Protection: &ProtectionOpts{Delete: &protect},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"POST /servers/", "GET /actions/2", "POST /servers/1/actions/change_protection", "GET /actions/3"}
+	if !reflect.DeepEqual(rr.recorded(), want) {
+		t.Errorf("expected requests %v, got %v", want, rr.recorded())
+	}
+	body := rr.body("POST /servers/1/actions/change_protection")
+	if body["delete"] != true {
+		t.Errorf("expected delete protection to be requested, got %v", body)
+	}
+	if _, ok := body["rebuild"]; ok {
+		t.Errorf("expected rebuild protection to be left unchanged, got %v", body)
+	}
+	if !server.Server.Protection.Delete {
+		t.Error("expected the returned server to be delete protected")
+	}
+}
+
+func TestCreateServerWithProtectionFailure(t *testing.T) {
+	rr := newRequestRecorder(t)
+	rr.respond("POST /servers/", http.StatusCreated, `{"server":{"id":1,"name":"a"},"action":{"id":2,"status":"success","progress":100}}`)
+	rr.respond("GET /actions/2", http.StatusOK, `{"action":{"id":2,"status":"success","progress":100}}`)
+	rr.respond("POST /servers/1/actions/change_protection", http.StatusLocked, `{"error":{"code":"locked","message":"server is locked"}}`)
+	client := newRecorderClient(t, rr)
+
+	protect := true
+	server, _, err := client.CreateServer(context.Background(), ServerCreateOpts{
+		Name:       "a",
+		ServerType: "cx22",
+		Image:      "ubuntu-22.04",
+		Protection: &ProtectionOpts{Delete: &protect},
+	})
+	if err == nil {
+		t.Fatal("expected the protection error")
+	}
+	if server == nil || server.Server.ID != 1 {
+		t.Fatalf("expected the created server to be returned, got %v", server)
+	}
+	if server.Server.Protection.Delete {
+		t.Error("expected the returned server to be unprotected")
+	}
+}
+
+func TestDeleteServerWithOpts(t *testing.T) {
+	tests := []struct {
+		name      string
+		force     bool
+		protected bool
+		want      []string
+	}{
+		{
+			name: "not forced",
+			want: []string{"DELETE /servers/1"},
+		},
+		{
+			name:  "forced unprotected",
+			force: true,
+			want:  []string{"GET /servers/1", "DELETE /servers/1"},
+		},
+		{
+			name:      "forced protected",
+			force:     true,
+			protected: true,
+			want:      []string{"GET /servers/1", "POST /servers/1/actions/change_protection", "GET /actions/7", "DELETE /servers/1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := newRequestRecorder(t)
+			protection := `{"delete":false,"rebuild":true}`
+			if tt.protected {
+				protection = `{"delete":true,"rebuild":true}`
+			}
+			rr.respond("GET /servers/1", http.StatusOK, `{"server":{"id":1,"name":"a","protection":`+protection+`}}`)
+			rr.respond("POST /servers/1/actions/change_protection", http.StatusCreated, `{"action":{"id":7,"status":"running"}}`)
+			rr.respond("GET /actions/7", http.StatusOK, `{"action":{"id":7,"status":"success","progress":100}}`)
+			rr.respond("DELETE /servers/1", http.StatusOK, `{"action":{"id":8,"command":"delete_server","status":"running"}}`)
+			client := newRecorderClient(t, rr)
+
+			action, err := client.DeleteServerWithOpts(context.Background(), 1, ServerDeleteOpts{Force: tt.force})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if action.Server.ID != 8 {
+				t.Errorf("expected delete action 8, got %d", action.Server.ID)
+			}
+			if !reflect.DeepEqual(rr.recorded(), tt.want) {
+				t.Errorf("expected requests %v, got %v", tt.want, rr.recorded())
+			}
+			if tt.protected {
+				body := rr.body("POST /servers/1/actions/change_protection")
+				if body["delete"] != false || body["rebuild"] != true {
+					t.Errorf("expected delete protection removed and rebuild protection kept, got %v", body)
+				}
+			}
+		})
+	}
+}
+
+func TestModifyServerLabels(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Client) error
+		want   map[string]interface{}
+	}{
+		{
+			name: "add",
+			modify: func(c *Client) error {
+				_, err := c.AddServerLabels(context.Background(), 1, map[string]string{"team": "web", "env": "prod"})
+				return err
+			},
+			want: map[string]interface{}{"env": "prod", "tier": "frontend", "team": "web"},
+		},
+		{
+			name: "remove",
+			modify: func(c *Client) error {
+				_, err := c.RemoveServerLabels(context.Background(), 1, []string{"env", "missing"})
+				return err
+			},
+			want: map[string]interface{}{"tier": "frontend"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := newRequestRecorder(t)
+			rr.respond("GET /servers/1", http.StatusOK, `{"server":{"id":1,"name":"a","labels":{"env":"dev","tier":"frontend"}}}`)
+			rr.respond("PUT /servers/1", http.StatusOK, `{"server":{"id":1,"name":"a"}}`)
+			client := newRecorderClient(t, rr)
+
+			if err := tt.modify(client); err != nil {
+				t.Fatal(err)
+			}
+			if labels := rr.body("PUT /servers/1")["labels"]; !reflect.DeepEqual(labels, tt.want) {
+				t.Errorf("expected labels %v, got %v", tt.want, labels)
+			}
+		})
+	}
+}
+
+func TestGetServersByIDs(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, `{"servers":[{"id":1,"name":"a"},{"id":2,"name":"b"},{"id":3,"name":"c"}]}`)
+	})
+
+	servers, errs := client.GetServersByIDs(context.Background(), []int{1, 3, 4})
+	if len(servers) != 2 || servers[1].Name != "a" || servers[3].Name != "c" {
+		t.Errorf("expected servers a and c, got %v", servers)
+	}
+	if len(errs) != 1 || !IsError(errs[4], ErrorCodeNotFound) {
+		t.Errorf("expected a not_found error for server 4 only, got %v", errs)
+	}
+}
+
+func TestGetServersByIDsListingError(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusUnauthorized, `{"error":{"code":"unauthorized","message":"unable to authenticate"}}`)
+	})
+
+	servers, errs := client.GetServersByIDs(context.Background(), []int{1, 2})
+	if len(servers) != 0 {
+		t.Errorf("expected no servers, got %v", servers)
+	}
+	if len(errs) != 2 || !IsError(errs[1], "unauthorized") || !IsError(errs[2], "unauthorized") {
+		t.Errorf("expected the listing error for every ID, got %v", errs)
+	}
+}
+
+func TestCreateServerSendsUserDataForAppImages(t *testing.T) {
+	const userData = "#cloud-config\nruncmd:\n  - echo ready\n"
+	rr := newRequestRecorder(t)
+	rr.respond("POST /servers/", http.StatusCreated, `{"server":{"id":1,"name":"a"},"action":{"id":2}}`)
+	client := newRecorderClient(t, rr)
+
+	_, _, err := client.CreateServer(context.Background(), ServerCreateOpts{
+		Name:       "a",
+		ServerType: "cx22",
+		Image:      "wordpress",
+		UserData:   userData,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := rr.body("POST /servers/")
+	if body["image"] != "wordpress" || body["user_data"] != userData {
+		t.Errorf("expected the app image with its user data, got %v", body)
+	}
+}
+
+func TestCreateServerRejectsInvalidUserData(t *testing.T) {
+	tests := map[string]string{
+		"too large":     "#cloud-config\n" + strings.Repeat("a", maxUserDataSize),
+		"invalid UTF-8": "#cloud-config\n\xff\xfe",
+	}
+	for name, userData := range tests {
+		t.Run(name, func(t *testing.T) {
+			rr := newRequestRecorder(t)
+			client := newRecorderClient(t, rr)
+
+			_, _, err := client.CreateServer(context.Background(), ServerCreateOpts{
+				Name:       "a",
+				ServerType: "cx22",
+				Image:      "wordpress",
+				UserData:   userData,
+			})
+			if err == nil {
+				t.Error("expected the user data to be rejected")
+			}
+			if len(rr.recorded()) != 0 {
+				t.Errorf("expected no requests, got %v", rr.recorded())
+			}
+		})
+	}
+}
+
+func TestUserDataFromFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		want     string
+		wantErr  error
+		rejected bool
+	}{
+		{name: "cloud-config", content: "#cloud-config\npackages: [nginx]\n", want: "#cloud-config\npackages: [nginx]\n"},
+		{name: "script", content: "#!/bin/sh\necho ready\n", want: "#!/bin/sh\necho ready\n"},
+		{name: "unknown format", content: "packages: [nginx]\n", want: "packages: [nginx]\n", wantErr: ErrUnknownUserDataFormat},
+		{name: "too large", content: "#cloud-config\n" + strings.Repeat("a", maxUserDataSize), rejected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "user-data")
+			if err := ioutil.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			userData, err := UserDataFromFile(path)
+			if tt.rejected {
+				if err == nil || err == ErrUnknownUserDataFormat || userData != "" {
+					t.Errorf("expected the user data to be rejected, got %q, %v", userData, err)
+				}
+				return
+			}
+			if err != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if userData != tt.want {
+				t.Errorf("expected user data %q, got %q", tt.want, userData)
+			}
+		})
+	}
+}
+
+func TestDiffServers(t *testing.T) {
+	server := func(name string) *models.Server {
+		return &models.Server{Server: models.ServerClass{Name: name}}
+	}
+	opts := func(names ...string) []ServerCreateOpts {
+		var o []ServerCreateOpts
+		for _, name := range names {
+			o = append(o, ServerCreateOpts{Name: name, ServerType: "cx22", Image: "ubuntu-22.04"})
+		}
+		return o
+	}
+	names := func(servers []*models.Server) []string {
+		var n []string
+		for _, s := range servers {
+			n = append(n, s.Server.Name)
+		}
+		return n
+	}
+	createNames := func(o []ServerCreateOpts) []string {
+		var n []string
+		for _, opts := range o {
+			n = append(n, opts.Name)
+		}
+		return n
+	}
+
+	tests := []struct {
+		name       string
+		desired    []ServerCreateOpts
+		actual     []*models.Server
+		wantCreate []string
+		wantDelete []string
+	}{
+		{name: "empty"},
+		{name: "adds", desired: opts("a", "b"), wantCreate: []string{"a", "b"}},
+		{name: "deletes", actual: []*models.Server{server("a"), server("b")}, wantDelete: []string{"a", "b"}},
+		{name: "no-ops", desired: opts("a", "b"), actual: []*models.Server{server("b"), server("a")}},
+		{
+			name:       "mixed",
+			desired:    opts("a", "c", "d"),
+			actual:     []*models.Server{server("b"), server("a"), nil, server("e")},
+			wantCreate: []string{"c", "d"},
+			wantDelete: []string{"b", "e"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toCreate, toDelete := DiffServers(tt.desired, tt.actual)
+			if got := createNames(toCreate); !reflect.DeepEqual(got, tt.wantCreate) {
+				t.Errorf("expected to create %v, got %v", tt.wantCreate, got)
+			}
+			if got := names(toDelete); !reflect.DeepEqual(got, tt.wantDelete) {
+				t.Errorf("expected to delete %v, got %v", tt.wantDelete, got)
+			}
+		})
+	}
+}
+
+func TestGetServersNewestFirst(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if got := q["sort"]; !reflect.DeepEqual(got, []string{"created:desc"}) {
+			t.Errorf("expected sort=created:desc, got %v", got)
+		}
+		if q.Get("label_selector") != "env=prod" {
+			t.Errorf("expected the label selector to be kept, got %v", q)
+		}
+		writeJSON(w, http.StatusOK, `{"servers":[
+			{"id":3,"name":"c","created":"2023-03-01T00:00:00+00:00"},
+			{"id":2,"name":"b","created":"2023-02-01T00:00:00+00:00"},
+			{"id":1,"name":"a","created":"2023-01-01T00:00:00+00:00"}
+		]}`)
+	})
+
+	servers, err := client.GetServersNewestFirst(context.Background(), ListOpts{LabelSelector: "env=prod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int64
+	for i, s := range servers.Servers {
+		ids = append(ids, s.ID)
+		if i > 0 && s.Created.After(servers.Servers[i-1].Created) {
+			t.Errorf("server %d is newer than the one before it", s.ID)
+		}
+	}
+	if !reflect.DeepEqual(ids, []int64{3, 2, 1}) {
+		t.Errorf("expected the API's order 3, 2, 1, got %v", ids)
+	}
+}
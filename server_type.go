@@ -0,0 +1,72 @@
+package gohetz
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"./models"
+)
+
+const serverTypesUrl = "/server_types"
+
+// GetDeprecatedServerTypes returns all server types that have been
+// announced for deprecation.
+func (c *Client) GetDeprecatedServerTypes(ctx context.Context) ([]*models.ServerType, error) {
+	var deprecated []*models.ServerType
+	_, err := c.all(func(page int) (*Response, error) {
+		serverTypes, resp, err := c.listServerTypes(ctx, valuesForListOpts(allListOpts(ListOpts{Page: page})))
+		if err != nil {
+			return resp, err
+		}
+		for i := range serverTypes.ServerTypes {
+			if serverTypes.ServerTypes[i].Deprecation != nil {
+				deprecated = append(deprecated, &serverTypes.ServerTypes[i])
+			}
+		}
+		return resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deprecated, nil
+}
+
+func (c *Client) getServerTypeByName(ctx context.Context, name string) (*models.ServerType, error) {
+	vals := url.Values{}
+	vals.Add("name", name)
+	serverTypes, _, err := c.listServerTypes(ctx, vals)
+	if err != nil {
+		return nil, err
+	}
+	if len(serverTypes.ServerTypes) == 0 {
+		return nil, fmt.Errorf("hcloud: server type %q not found", name)
+	}
+	return &serverTypes.ServerTypes[0], nil
+}
+
+func (c *Client) listServerTypes(ctx context.Context, vals url.Values) (*models.ServerTypesList, *Response, error) {
+	req, err := c.NewRequest(ctx, http.MethodGet, serverTypesUrl+"?"+vals.Encode(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw, err := c.Do(req, nil)
+	if err != nil {
+		return nil, raw, err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(raw.Body)
+	if err != nil {
+		return nil, raw, err
+	}
+
+	var serverTypes models.ServerTypesList
+	if err := c.decode(bodyBytes, &serverTypes); err != nil {
+		return nil, raw, err
+	}
+
+	return &serverTypes, raw, nil
+}
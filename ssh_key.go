@@ -0,0 +1,261 @@
+package gohetz
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"./models"
+)
+
+const sshKeysUrl = "/ssh_keys/"
+
+// SSHKeyCreateOpts specifies options for creating an SSH key.
+type SSHKeyCreateOpts struct {
+	Name      string            // Name of the SSH key (required)
+	PublicKey string            // Public key in OpenSSH format (required)
+	Labels    map[string]string // User-defined labels
+}
+
+// SSHKeyUpdateOpts specifies options for updating an SSH key. Zero values
+// are left unchanged.
+type SSHKeyUpdateOpts struct {
+	Name   string            // New name
+	Labels map[string]string // New labels, replacing all existing ones
+}
+
+// GetAllSSHKeys returns the SSH keys matching opts, following pagination
+// until the last page. opts.Page is ignored.
+func (c *Client) GetAllSSHKeys(ctx context.Context, opts ListOpts) (*models.SSHKeys, error) {
+	opts = allListOpts(opts)
+	sshKeys := &models.SSHKeys{}
+	_, err := c.all(func(page int) (*Response, error) {
+		opts.Page = page
+		p, resp, err := c.listSSHKeys(ctx, valuesForListOpts(opts))
+		if err != nil {
+			return resp, err
+		}
+		sshKeys.SSHKeys = append(sshKeys.SSHKeys, p.SSHKeys...)
+		return resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sshKeys, nil
+}
+
+// GetSSHKey returns the SSH key with the given ID. If the key does not
+// exist, nil is returned for both the key and the error.
+func (c *Client) GetSSHKey(ctx context.Context, id int) (*models.SSHKey, error) {
+	req, err := c.NewRequest(ctx, http.MethodGet, fmt.Sprintf("%s%d", sshKeysUrl, id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	bodyBytes, err := c.doSSHKeyRequest(req)
+	if IsError(err, ErrorCodeNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var sshKey models.SSHKey
+	if err := c.decode(bodyBytes, &sshKey); err != nil {
+		return nil, err
+	}
+
+	return &sshKey, nil
+}
+
+// GetSSHKeyByName returns the SSH key with the given name, or nil if there is none.
+func (c *Client) GetSSHKeyByName(ctx context.Context, name string) (*models.SSHKey, error) {
+	vals := url.Values{}
+	vals.Add("name", name)
+	return c.firstSSHKey(ctx, vals)
+}
+
+// GetSSHKeyByFingerprint returns the SSH key with the given fingerprint, or
+// nil if there is none.
+func (c *Client) GetSSHKeyByFingerprint(ctx context.Context, fingerprint string) (*models.SSHKey, error) {
+	vals := url.Values{}
+	vals.Add("fingerprint", fingerprint)
+	return c.firstSSHKey(ctx, vals)
+}
+
+// CreateSSHKey creates an SSH key.
+func (c *Client) CreateSSHKey(ctx context.Context, opts SSHKeyCreateOpts) (*models.SSHKey, error) {
+	if opts.Name == "" {
+		return nil, errors.New("hcloud: SSH key name is required")
+	}
+	if opts.PublicKey == "" {
+		return nil, errors.New("hcloud: SSH public key is required")
+	}
+
+	request := &models.SSHKeyCreateRequest{
+		Name:      opts.Name,
+		PublicKey: opts.PublicKey,
+	}
+	if opts.Labels != nil {
+		request.Labels = make(map[string]interface{}, len(opts.Labels))
+		for k, v := range opts.Labels {
+			request.Labels[k] = v
+		}
+	}
+	req, err := c.NewJSONRequest(ctx, http.MethodPost, sshKeysUrl, request)
+	if err != nil {
+		return nil, err
+	}
+
+	bodyBytes, err := c.doSSHKeyRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var sshKey models.SSHKey
+	if err := c.decode(bodyBytes, &sshKey); err != nil {
+		return nil, err
+	}
+
+	return &sshKey, nil
+}
+
+// UpdateSSHKey updates the SSH key with the given ID.
+func (c *Client) UpdateSSHKey(ctx context.Context, id int, opts SSHKeyUpdateOpts) (*models.SSHKey, error) {
+	request := &models.SSHKeyUpdateRequest{}
+	if opts.Name != "" {
+		request.Name = &opts.Name
+	}
+	if opts.Labels != nil {
+		labels := make(map[string]interface{}, len(opts.Labels))
+		for k, v := range opts.Labels {
+			labels[k] = v
+		}
+		request.Labels = &labels
+	}
+	req, err := c.NewJSONRequest(ctx, http.MethodPut, fmt.Sprintf("%s%d", sshKeysUrl, id), request)
+	if err != nil {
+		return nil, err
+	}
+
+	bodyBytes, err := c.doSSHKeyRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var sshKey models.SSHKey
+	if err := c.decode(bodyBytes, &sshKey); err != nil {
+		return nil, err
+	}
+
+	return &sshKey, nil
+}
+
+// DeleteSSHKey deletes the SSH key with the given ID.
+func (c *Client) DeleteSSHKey(ctx context.Context, id int) error {
+	req, err := c.NewRequest(ctx, http.MethodDelete, fmt.Sprintf("%s%d", sshKeysUrl, id), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Do(req, nil)
+	return err
+}
+
+func (c *Client) firstSSHKey(ctx context.Context, vals url.Values) (*models.SSHKey, error) {
+	sshKeys, _, err := c.listSSHKeys(ctx, vals)
+	if err != nil {
+		return nil, err
+	}
+	if len(sshKeys.SSHKeys) == 0 {
+		return nil, nil
+	}
+	return &models.SSHKey{SSHKey: sshKeys.SSHKeys[0]}, nil
+}
+
+func (c *Client) listSSHKeys(ctx context.Context, vals url.Values) (*models.SSHKeys, *Response, error) {
+	req, err := c.NewRequest(ctx, http.MethodGet, sshKeysUrl+"?"+vals.Encode(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw, err := c.Do(req, nil)
+	if err != nil {
+		return nil, raw, err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(raw.Body)
+	if err != nil {
+		return nil, raw, err
+	}
+
+	var sshKeys models.SSHKeys
+	if err := c.decode(bodyBytes, &sshKeys); err != nil {
+		return nil, raw, err
+	}
+
+	return &sshKeys, raw, nil
+}
+
+func (c *Client) doSSHKeyRequest(req *http.Request) ([]byte, error) {
+	raw, err := c.Do(req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return ioutil.ReadAll(raw.Body)
+}
+
+// SSHKeyFingerprint returns the MD5 fingerprint of an OpenSSH public key in
+// the colon-separated hex format the API reports, e.g. "b7:2f:30:...".
+func SSHKeyFingerprint(publicKey string) (string, error) {
+	fields := strings.Fields(publicKey)
+	if len(fields) < 2 {
+		return "", errors.New("hcloud: public key is not in OpenSSH format")
+	}
+	key, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return "", fmt.Errorf("hcloud: public key is not in OpenSSH format: %s", err)
+	}
+
+	sum := md5.Sum(key)
+	hex := make([]string, len(sum))
+	for i, b := range sum {
+		hex[i] = fmt.Sprintf("%02x", b)
+	}
+	return strings.Join(hex, ":"), nil
+}
+
+// EnsureSSHKey returns the SSH key with the fingerprint of publicKey,
+// creating it under name if there is none. It is safe to call repeatedly: if
+// a concurrent call creates the key first, the resulting uniqueness error is
+// resolved by fetching the key again.
+func (c *Client) EnsureSSHKey(ctx context.Context, name, publicKey string) (*models.SSHKey, error) {
+	fingerprint, err := SSHKeyFingerprint(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	sshKey, err := c.GetSSHKeyByFingerprint(ctx, fingerprint)
+	if err != nil || sshKey != nil {
+		return sshKey, err
+	}
+
+	sshKey, err = c.CreateSSHKey(ctx, SSHKeyCreateOpts{Name: name, PublicKey: publicKey})
+	if err == nil || !(IsError(err, ErrorCodeUniquenessError) || IsConflict(err)) {
+		return sshKey, err
+	}
+	existing, getErr := c.GetSSHKeyByFingerprint(ctx, fingerprint)
+	if getErr != nil {
+		return nil, getErr
+	}
+	if existing == nil {
+		return nil, err
+	}
+	return existing, nil
+}
@@ -0,0 +1,106 @@
+package gohetz
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const (
+	testPublicKey   = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIBDtlCiqM/szqa0hFa6mCIxkwPHIlX9vSVzHWIg4URUq test"
+	testFingerprint = "8b:e9:24:05:f1:d8:84:6c:49:e3:d7:c7:97:61:ba:b6"
+)
+
+func TestSSHKeyFingerprint(t *testing.T) {
+	fingerprint, err := SSHKeyFingerprint(testPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fingerprint != testFingerprint {
+		t.Errorf("expected %s, got %s", testFingerprint, fingerprint)
+	}
+	if _, err := SSHKeyFingerprint("not a key"); err == nil {
+		t.Error("expected an error for an invalid key")
+	}
+}
+
+func TestEnsureSSHKey(t *testing.T) {
+	const (
+		found   = `{"ssh_keys":[{"id":3,"name":"deploy","fingerprint":"` + testFingerprint + `"}]}`
+		missing = `{"ssh_keys":[]}`
+	)
+	tests := []struct {
+		name    string
+		lookups []string
+		create  func(w http.ResponseWriter, r *http.Request)
+		want    []string
+	}{
+		{
+			name:    "find",
+			lookups: []string{found},
+			want:    []string{"GET /ssh_keys/"},
+		},
+		{
+			name:    "create",
+			lookups: []string{missing},
+			create: func(w http.ResponseWriter, r *http.Request) {
+				writeJSON(w, http.StatusCreated, `{"ssh_key":{"id":3,"name":"deploy","fingerprint":"`+testFingerprint+`"}}`)
+			},
+			want: []string{"GET /ssh_keys/", "POST /ssh_keys/"},
+		},
+		{
+			name:    "race",
+			lookups: []string{missing, found},
+			create: func(w http.ResponseWriter, r *http.Request) {
+				writeJSON(w, http.StatusConflict, `{"error":{"code":"uniqueness_error","message":"SSH key with the same fingerprint already exists"}}`)
+			},
+			want: []string{"GET /ssh_keys/", "POST /ssh_keys/", "GET /ssh_keys/"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := newRequestRecorder(t)
+			lookups := tt.lookups
+			rr.responses["GET /ssh_keys/"] = func(w http.ResponseWriter, r *http.Request) {
+				if got := r.URL.Query().Get("fingerprint"); got != testFingerprint {
+					t.Errorf("expected lookup by fingerprint %s, got %q", testFingerprint, got)
+				}
+				if len(lookups) == 0 {
+					t.Error("unexpected lookup")
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				writeJSON(w, http.StatusOK, lookups[0])
+				lookups = lookups[1:]
+			}
+			if tt.create != nil {
+				rr.responses["POST /ssh_keys/"] = tt.create
+			}
+			client := newRecorderClient(t, rr)
+
+			sshKey, err := client.EnsureSSHKey(context.Background(), "deploy", testPublicKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sshKey.SSHKey.ID != 3 {
+				t.Errorf("expected SSH key 3, got %d", sshKey.SSHKey.ID)
+			}
+			if !reflect.DeepEqual(rr.recorded(), tt.want) {
+				t.Errorf("expected requests %v, got %v", tt.want, rr.recorded())
+			}
+		})
+	}
+}
+
+func TestEnsureSSHKeyCreateError(t *testing.T) {
+	rr := newRequestRecorder(t)
+	rr.respond("GET /ssh_keys/", http.StatusOK, `{"ssh_keys":[]}`)
+	rr.respond("POST /ssh_keys/", http.StatusConflict, `{"error":{"code":"uniqueness_error","message":"SSH key with the same name already exists"}}`)
+	client := newRecorderClient(t, rr)
+
+	_, err := client.EnsureSSHKey(context.Background(), "deploy", testPublicKey)
+	if !IsError(err, ErrorCodeUniquenessError) {
+		t.Fatalf("expected uniqueness error when no key has the fingerprint, got %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package gohetz
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+)
+
+// WaitForTCP blocks until a TCP connection to addr (host:port) can be
+// established, retrying every interval, which must be positive. It is meant
+// to be chained after a server reports running, e.g. to wait for SSH on
+// port 22. Each attempt times out after interval, so an address dropping
+// packets does not stall the loop. It returns ctx.Err() if ctx is done first.
+func WaitForTCP(ctx context.Context, addr string, interval time.Duration) error {
+	if interval <= 0 {
+		return errors.New("hcloud: interval must be positive")
+	}
+
+	dialer := net.Dialer{Timeout: interval}
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	for {
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err == nil {
+			return conn.Close()
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(interval)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
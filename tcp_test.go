@@ -0,0 +1,64 @@
+package gohetz
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestWaitForTCP(t *testing.T) {
+	// Reserve a free port, then only start listening on it after a delay.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	listening := make(chan net.Listener, 1)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			t.Error(err)
+			close(listening)
+			return
+		}
+		listening <- l
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	if err := WaitForTCP(ctx, addr, 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("expected to wait for the listener, returned after %s", elapsed)
+	}
+	if l, ok := <-listening; ok {
+		l.Close()
+	}
+}
+
+func TestWaitForTCPContextDone(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := WaitForTCP(ctx, addr, 10*time.Millisecond); err != context.DeadlineExceeded {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestWaitForTCPInvalidInterval(t *testing.T) {
+	if err := WaitForTCP(context.Background(), "127.0.0.1:22", 0); err == nil {
+		t.Error("expected an error for a zero interval")
+	}
+}
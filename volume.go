@@ -0,0 +1,283 @@
+package gohetz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"./models"
+)
+
+const volumesUrl = "/volumes/"
+
+// VolumeCreateOpts specifies options for creating a volume.
+type VolumeCreateOpts struct {
+	Name      string            // Name of the volume (required)
+	Size      int               // Size of the volume in GB (required)
+	Location  string            // Location to create the volume in; either Location or Server is required
+	Server    int               // ID of the server to attach the volume to once created
+	Automount *bool             // Mount the volume after attaching it; requires Server
+	Format    string            // Filesystem to format the volume with, e.g. "ext4" or "xfs"
+	Labels    map[string]string // User-defined labels
+}
+
+func (o VolumeCreateOpts) validate() error {
+	switch {
+	case o.Name == "":
+		return errors.New("hcloud: volume name is required")
+	case o.Size <= 0:
+		return errors.New("hcloud: volume size must be positive")
+	case o.Location == "" && o.Server == 0:
+		return errors.New("hcloud: volume location or server is required")
+	case o.Location != "" && o.Server != 0:
+		return errors.New("hcloud: volume location and server are mutually exclusive")
+	case o.Automount != nil && o.Server == 0:
+		return errors.New("hcloud: volume automount requires a server")
+	}
+	return nil
+}
+
+func (o VolumeCreateOpts) request() *models.VolumeCreateRequest {
+	request := &models.VolumeCreateRequest{
+		Name:      o.Name,
+		Size:      o.Size,
+		Automount: o.Automount,
+	}
+	if o.Location != "" {
+		request.Location = &o.Location
+	}
+	if o.Server != 0 {
+		request.Server = &o.Server
+	}
+	if o.Format != "" {
+		request.Format = &o.Format
+	}
+	if o.Labels != nil {
+		request.Labels = make(map[string]interface{}, len(o.Labels))
+		for k, v := range o.Labels {
+			request.Labels[k] = v
+		}
+	}
+	return request
+}
+
+// VolumeUpdateOpts specifies options for updating a volume. Zero values are
+// left unchanged.
+type VolumeUpdateOpts struct {
+	Name   string            // New name
+	Labels map[string]string // New labels, replacing all existing ones
+}
+
+// GetAllVolumes returns the volumes matching opts, following pagination
+// until the last page. opts.Page is ignored.
+func (c *Client) GetAllVolumes(ctx context.Context, opts ListOpts) (*models.Volumes, error) {
+	opts = allListOpts(opts)
+	volumes := &models.Volumes{}
+	_, err := c.all(func(page int) (*Response, error) {
+		opts.Page = page
+		p, resp, err := c.listVolumes(ctx, valuesForListOpts(opts))
+		if err != nil {
+			return resp, err
+		}
+		volumes.Volumes = append(volumes.Volumes, p.Volumes...)
+		return resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return volumes, nil
+}
+
+// GetVolume returns the volume with the given ID. If the volume does not
+// exist, nil is returned for both the volume and the error.
+func (c *Client) GetVolume(ctx context.Context, id int) (*models.Volume, error) {
+	req, err := c.NewRequest(ctx, http.MethodGet, fmt.Sprintf("%s%d", volumesUrl, id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := c.Do(req, nil)
+	if IsError(err, ErrorCodeNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(raw.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var volume models.Volume
+	if err := c.decode(bodyBytes, &volume); err != nil {
+		return nil, err
+	}
+
+	return &volume, nil
+}
+
+// VolumeExists reports whether the volume with the given ID exists.
+// Errors other than the API reporting the volume as not found are returned.
+func (c *Client) VolumeExists(ctx context.Context, id int) (bool, error) {
+	volume, err := c.GetVolume(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return volume != nil, nil
+}
+
+// CreateVolume creates a volume. It returns the created volume and the
+// action creating it, which can be passed to WaitForAction.
+func (c *Client) CreateVolume(ctx context.Context, opts VolumeCreateOpts) (*models.Volume, *models.Action, error) {
+	if err := opts.validate(); err != nil {
+		return nil, nil, err
+	}
+
+	req, err := c.NewJSONRequest(ctx, http.MethodPost, volumesUrl, opts.request())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw, err := c.Do(req, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(raw.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var resp models.VolumeCreateResponse
+	if err := c.decode(bodyBytes, &resp); err != nil {
+		return nil, nil, err
+	}
+
+	return &models.Volume{Volume: resp.Volume}, &models.Action{Server: resp.Action}, nil
+}
+
+// UpdateVolume updates the volume with the given ID.
+func (c *Client) UpdateVolume(ctx context.Context, id int, opts VolumeUpdateOpts) (*models.Volume, error) {
+	request := &models.VolumeUpdateRequest{}
+	if opts.Name != "" {
+		request.Name = &opts.Name
+	}
+	if opts.Labels != nil {
+		labels := make(map[string]interface{}, len(opts.Labels))
+		for k, v := range opts.Labels {
+			labels[k] = v
+		}
+		request.Labels = &labels
+	}
+	req, err := c.NewJSONRequest(ctx, http.MethodPut, fmt.Sprintf("%s%d", volumesUrl, id), request)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := c.Do(req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(raw.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var volume models.Volume
+	if err := c.decode(bodyBytes, &volume); err != nil {
+		return nil, err
+	}
+
+	return &volume, nil
+}
+
+// DeleteVolume deletes the volume with the given ID. The volume must be
+// detached first.
+func (c *Client) DeleteVolume(ctx context.Context, id int) error {
+	req, err := c.NewRequest(ctx, http.MethodDelete, fmt.Sprintf("%s%d", volumesUrl, id), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Do(req, nil)
+	return err
+}
+
+// AttachVolume attaches the volume with the given ID to a server in the same
+// location. If the volume is already attached to a server, the returned
+// error satisfies IsConflict.
+func (c *Client) AttachVolume(ctx context.Context, volumeID, serverID int, automount bool) (*models.Action, error) {
+	return c.doVolumeAction(ctx, volumeID, "attach", &models.VolumeAttachRequest{
+		Server:    serverID,
+		Automount: &automount,
+	})
+}
+
+// DetachVolume detaches the volume with the given ID from its server.
+func (c *Client) DetachVolume(ctx context.Context, volumeID int) (*models.Action, error) {
+	return c.doVolumeAction(ctx, volumeID, "detach", nil)
+}
+
+// ResizeVolume grows the volume with the given ID to size GB. Volumes can
+// only be made larger.
+func (c *Client) ResizeVolume(ctx context.Context, volumeID, size int) (*models.Action, error) {
+	if size <= 0 {
+		return nil, errors.New("hcloud: volume size must be positive")
+	}
+	return c.doVolumeAction(ctx, volumeID, "resize", &models.VolumeResizeRequest{Size: size})
+}
+
+// doVolumeAction posts body to the given action of a volume and decodes the
+// action it starts.
+func (c *Client) doVolumeAction(ctx context.Context, id int, action string, body interface{}) (*models.Action, error) {
+	req, err := c.NewJSONRequest(ctx, http.MethodPost, fmt.Sprintf("%s%d/actions/%s", volumesUrl, id, action), body)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := c.Do(req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(raw.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var a models.Action
+	if err := c.decode(bodyBytes, &a); err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+func (c *Client) listVolumes(ctx context.Context, vals url.Values) (*models.Volumes, *Response, error) {
+	req, err := c.NewRequest(ctx, http.MethodGet, volumesUrl+"?"+vals.Encode(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw, err := c.Do(req, nil)
+	if err != nil {
+		return nil, raw, err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(raw.Body)
+	if err != nil {
+		return nil, raw, err
+	}
+
+	var volumes models.Volumes
+	if err := c.decode(bodyBytes, &volumes); err != nil {
+		return nil, raw, err
+	}
+
+	return &volumes, raw, nil
+}
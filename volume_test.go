@@ -0,0 +1,56 @@
+package gohetz
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestAttachVolumeConflict(t *testing.T) {
+	tests := []struct {
+		name         string
+		respond      func(w http.ResponseWriter)
+		wantConflict bool
+	}{
+		{
+			name: "conflict error",
+			respond: func(w http.ResponseWriter) {
+				writeJSON(w, http.StatusConflict, `{"error":{"code":"conflict","message":"volume is already attached to a server"}}`)
+			},
+			wantConflict: true,
+		},
+		{
+			name: "no error body",
+			respond: func(w http.ResponseWriter) {
+				w.Header().Set("Content-Type", "text/plain")
+				w.WriteHeader(http.StatusConflict)
+				w.Write([]byte("conflict"))
+			},
+			wantConflict: true,
+		},
+		{
+			name: "other error code",
+			respond: func(w http.ResponseWriter) {
+				writeJSON(w, http.StatusConflict, `{"error":{"code":"uniqueness_error","message":"name is already used"}}`)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				tt.respond(w)
+			})
+
+			_, err := client.AttachVolume(context.Background(), 2, 1, false)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if IsConflict(err) != tt.wantConflict {
+				t.Errorf("expected IsConflict to be %t, got error %v", tt.wantConflict, err)
+			}
+			if err.Error() == "" {
+				t.Error("expected an error message")
+			}
+		})
+	}
+}